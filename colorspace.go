@@ -0,0 +1,550 @@
+package main
+
+import (
+	"math"
+
+	"github.com/gookit/color"
+)
+
+
+/* Floored modulo, so that mod(-30, 360) == 330. */
+func mod(x, m float64) float64 {
+	x = math.Mod(x, m)
+	if x < 0 {
+		x += m
+	}
+	return x
+}
+
+
+func clamp01(x float64) float64 {
+	return math.Max(0, math.Min(1, x))
+}
+
+
+/* Convert sRGB components in [0, 1] to a (background) RGBColor, clamping any
+ * out-of-range components. */
+func rgbFromFloats(r, g, b float64) color.RGBColor {
+	return color.RGB(
+		uint8(math.Round(clamp01(r) * 255)),
+		uint8(math.Round(clamp01(g) * 255)),
+		uint8(math.Round(clamp01(b) * 255)),
+		true,
+	)
+}
+
+
+/* https://www.w3.org/TR/css-color-4/#hsl-to-rgb
+ * Hue is in degrees, saturation and lightness are in [0, 1]. */
+func hslToRGB(hue, saturation, lightness float64) (r, g, b float64) {
+	f := func(n float64) float64 {
+		k := mod(n + hue / 30, 12)
+		a := saturation * math.Min(lightness, 1 - lightness)
+		return lightness - a * math.Max(-1, math.Min(k - 3, math.Min(9 - k, 1)))
+	}
+	return f(0), f(8), f(4)
+}
+
+
+/* https://en.wikipedia.org/wiki/HSL_and_HSV#HSV_to_RGB_alternative
+ * Hue is in degrees, saturation and value are in [0, 1]. */
+func hsvToRGB(hue, saturation, value float64) (r, g, b float64) {
+	f := func(n float64) float64 {
+		k := mod(n + hue / 60, 6)
+		return value - value * saturation * math.Max(0, math.Min(k, math.Min(4 - k, 1)))
+	}
+	return f(5), f(3), f(1)
+}
+
+
+/* CIE XYZ tristimulus values of reference whites, with Y normalized to 1. */
+type whitePoint struct {
+	name    string
+	x, y, z float64
+}
+
+var whiteD65 = whitePoint{"D65", 0.95047, 1.0, 1.08883}
+var whiteD50 = whitePoint{"D50", 0.96422, 1.0, 0.82521}
+
+
+/* https://en.wikipedia.org/wiki/CIELAB_color_space#From_CIELAB_to_CIEXYZ */
+func labToXYZ(l, a, b float64, white whitePoint) (x, y, z float64) {
+	const delta = 6.0 / 29.0
+	finv := func(t float64) float64 {
+		if t > delta {
+			return t * t * t
+		}
+		return 3 * delta * delta * (t - 4.0 / 29.0)
+	}
+	fy := (l + 16) / 116
+	fx := fy + a / 500
+	fz := fy - b / 200
+	return white.x * finv(fx), white.y * finv(fy), white.z * finv(fz)
+}
+
+
+/* Bradford chromatic adaptation from D50 to D65.
+ * http://www.brucelindbloom.com/index.html?Eqn_ChromAdapt.html */
+func xyzD50ToD65(x, y, z float64) (float64, float64, float64) {
+	return  0.9555766 * x - 0.0230393 * y + 0.0631636 * z,
+		-0.0282895 * x + 1.0099416 * y + 0.0210077 * z,
+		 0.0122982 * x - 0.0204830 * y + 1.3299098 * z
+}
+
+
+/* D65 XYZ to linear-light sRGB. https://www.w3.org/TR/css-color-4/#color-conversion-code */
+func xyzToLinearSRGB(x, y, z float64) (r, g, b float64) {
+	r =  3.2409699419045226 * x - 1.537383177570094  * y - 0.4986107602930034  * z
+	g = -0.9692436362808796 * x + 1.8759675015077202 * y + 0.04155505740717559 * z
+	b =  0.05563007969699366 * x - 0.20397695888897652 * y + 1.0569715142428786 * z
+	return
+}
+
+
+/* sRGB transfer function, applied to linear-light components in [0, 1]. */
+func linearToSRGB(c float64) float64 {
+	sign := 1.0
+	if c < 0 {
+		sign, c = -1, -c
+	}
+	if c <= 0.0031308 {
+		return sign * 12.92 * c
+	}
+	return sign * (1.055 * math.Pow(c, 1 / 2.4) - 0.055)
+}
+
+
+/* Convert CIE L*a*b* relative to the given white point to sRGB components.
+ * The result may be outside [0, 1] if the color is outside the sRGB gamut. */
+func labToSRGB(l, a, b float64, white whitePoint) (float64, float64, float64) {
+	x, y, z := labToXYZ(l, a, b, white)
+	if white == whiteD50 {
+		x, y, z = xyzD50ToD65(x, y, z)
+	}
+	r, g, bl := xyzToLinearSRGB(x, y, z)
+	return linearToSRGB(r), linearToSRGB(g), linearToSRGB(bl)
+}
+
+
+/* Whether sRGB components are displayable, allowing for the rounding done by
+ * rgbFromFloats. */
+func inSRGBGamut(r, g, b float64) bool {
+	const epsilon = 0.5 / 255
+	for _, c := range [3]float64{r, g, b} {
+		if c < -epsilon || c > 1 + epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+
+/* Inverse of linearToSRGB. */
+func srgbToLinear(c float64) float64 {
+	sign := 1.0
+	if c < 0 {
+		sign, c = -1, -c
+	}
+	if c <= 0.04045 {
+		return sign * c / 12.92
+	}
+	return sign * math.Pow((c + 0.055) / 1.055, 2.4)
+}
+
+
+/* https://bottosson.github.io/posts/oklab/ */
+func linearSRGBToOKLab(r, g, b float64) (l, a, bb float64) {
+	lc := math.Cbrt(0.4122214708 * r + 0.5363325363 * g + 0.0514459929 * b)
+	mc := math.Cbrt(0.2119034982 * r + 0.6806995451 * g + 0.1073969566 * b)
+	sc := math.Cbrt(0.0883024619 * r + 0.2817188376 * g + 0.6299787005 * b)
+	l  = 0.2104542553 * lc + 0.7936177850 * mc - 0.0040720468 * sc
+	a  = 1.9779984951 * lc - 2.4285922050 * mc + 0.4505937099 * sc
+	bb = 0.0259040371 * lc + 0.7827717662 * mc - 0.8086757660 * sc
+	return
+}
+
+
+func okLabToLinearSRGB(l, a, b float64) (r, g, bb float64) {
+	lc := l + 0.3963377774 * a + 0.2158037573 * b
+	mc := l - 0.1055613458 * a - 0.0638541728 * b
+	sc := l - 0.0894841775 * a - 1.2914855480 * b
+	lc, mc, sc = lc * lc * lc, mc * mc * mc, sc * sc * sc
+	r  =  4.0767416621 * lc - 3.3077115913 * mc + 0.2309699292 * sc
+	g  = -1.2684380046 * lc + 2.6097574011 * mc - 0.3413193965 * sc
+	bb = -0.0041960863 * lc - 0.7034186147 * mc + 1.7076147010 * sc
+	return
+}
+
+
+func okLabToSRGB(l, a, b float64) (float64, float64, float64) {
+	r, g, bb := okLabToLinearSRGB(l, a, b)
+	return linearToSRGB(r), linearToSRGB(g), linearToSRGB(bb)
+}
+
+
+func srgbToOKLab(r, g, b float64) (float64, float64, float64) {
+	return linearSRGBToOKLab(srgbToLinear(r), srgbToLinear(g), srgbToLinear(b))
+}
+
+
+/* Lightness, chroma and hue in degrees from OKLab (or any other Lab-like
+ * space). */
+func labToLCH(l, a, b float64) (lightness, chroma, hue float64) {
+	return l, math.Hypot(a, b), mod(math.Atan2(b, a) * 180 / math.Pi, 360)
+}
+
+
+func lchToLab(lightness, chroma, hue float64) (l, a, b float64) {
+	rad := hue * math.Pi / 180
+	return lightness, chroma * math.Cos(rad), chroma * math.Sin(rad)
+}
+
+
+/* Map an OKLCH color into the sRGB gamut by reducing chroma until clipping is
+ * no longer noticeable, returning sRGB components in [0, 1].
+ * https://www.w3.org/TR/css-color-4/#binsearch */
+func gamutMapOKLCH(lightness, chroma, hue float64) (r, g, b float64) {
+	const jnd = 0.02
+	const epsilon = 0.0001
+
+	if lightness >= 1 {
+		return 1, 1, 1
+	}
+	if lightness <= 0 {
+		return 0, 0, 0
+	}
+
+	toSRGB := func(chroma float64) (float64, float64, float64) {
+		return okLabToSRGB(lchToLab(lightness, chroma, hue))
+	}
+	clip := func(r, g, b float64) (float64, float64, float64) {
+		return clamp01(r), clamp01(g), clamp01(b)
+	}
+	deltaEOK := func(chroma float64, r, g, b float64) float64 {
+		l1, a1, b1 := lchToLab(lightness, chroma, hue)
+		l2, a2, b2 := srgbToOKLab(r, g, b)
+		return math.Sqrt((l1 - l2) * (l1 - l2) + (a1 - a2) * (a1 - a2) + (b1 - b2) * (b1 - b2))
+	}
+
+	r, g, b = toSRGB(chroma)
+	if inSRGBGamut(r, g, b) {
+		return
+	}
+
+	r, g, b = clip(r, g, b)
+	if deltaEOK(chroma, r, g, b) < jnd {
+		return
+	}
+
+	min, max := 0.0, chroma
+	minInGamut := true
+	for max - min > epsilon {
+		current := (min + max) / 2
+		cr, cg, cb := toSRGB(current)
+		if minInGamut && inSRGBGamut(cr, cg, cb) {
+			min = current
+			continue
+		}
+		r, g, b = clip(cr, cg, cb)
+		e := deltaEOK(current, r, g, b)
+		if e < jnd {
+			if jnd - e < epsilon {
+				return
+			}
+			minInGamut = false
+			min = current
+		} else {
+			max = current
+		}
+	}
+	return
+}
+
+
+/* sRGB components of an RGBColor, in [0, 1]. */
+func rgbToFloats(rgbColor color.RGBColor) (r, g, b float64) {
+	return float64(rgbColor[0]) / 255, float64(rgbColor[1]) / 255, float64(rgbColor[2]) / 255
+}
+
+
+/* https://www.w3.org/TR/css-color-4/#hwb-to-rgb
+ * Whiteness and blackness are in [0, 1]. */
+func hwbToRGB(hue, whiteness, blackness float64) (r, g, b float64) {
+	if whiteness + blackness >= 1 {
+		gray := whiteness / (whiteness + blackness)
+		return gray, gray, gray
+	}
+	r, g, b = hslToRGB(hue, 1, 0.5)
+	scale := 1 - whiteness - blackness
+	return r * scale + whiteness, g * scale + whiteness, b * scale + whiteness
+}
+
+
+/* Linear-light Display P3 to D65 XYZ. https://www.w3.org/TR/css-color-4/#color-conversion-code */
+func linearP3ToXYZ(r, g, b float64) (x, y, z float64) {
+	x = 0.4865709486482162 * r + 0.26566769316909306 * g + 0.1982172852343625 * b
+	y = 0.2289745640697488 * r + 0.6917385218365064  * g + 0.079286914093745  * b
+	z = 0.0                    + 0.04511338185890264 * g + 1.043944368900976  * b
+	return
+}
+
+
+/* Linear-light sRGB to D65 XYZ. https://www.w3.org/TR/css-color-4/#color-conversion-code */
+func linearSRGBToXYZ(r, g, b float64) (x, y, z float64) {
+	x = 0.41239079926595934 * r + 0.357584339383878   * g + 0.1804807884018343  * b
+	y = 0.21263900587151027 * r + 0.715168678767756   * g + 0.07219231536073371 * b
+	z = 0.01933081871559182 * r + 0.11919477979462598 * g + 0.9505321522496607  * b
+	return
+}
+
+
+/* https://en.wikipedia.org/wiki/CIELAB_color_space#From_CIEXYZ_to_CIELAB */
+func xyzToLab(x, y, z float64, white whitePoint) (l, a, b float64) {
+	const delta = 6.0 / 29.0
+	f := func(t float64) float64 {
+		if t > delta * delta * delta {
+			return math.Cbrt(t)
+		}
+		return t / (3 * delta * delta) + 4.0 / 29.0
+	}
+	fx, fy, fz := f(x / white.x), f(y / white.y), f(z / white.z)
+	return 116 * fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+
+/* CIE L*a*b* (D65) of an RGBColor. */
+func rgbToLab(rgbColor color.RGBColor) (l, a, b float64) {
+	return rgbToLabWhite(rgbColor, whiteD65)
+}
+
+
+/* CIE L*a*b* of an RGBColor, relative to the given white point. */
+func rgbToLabWhite(rgbColor color.RGBColor, white whitePoint) (l, a, b float64) {
+	r, g, bl := rgbToFloats(rgbColor)
+	x, y, z := linearSRGBToXYZ(srgbToLinear(r), srgbToLinear(g), srgbToLinear(bl))
+	if white == whiteD50 {
+		x, y, z = xyzD65ToD50(x, y, z)
+	}
+	return xyzToLab(x, y, z, white)
+}
+
+
+/* CIEDE2000 color difference between two RGBColors.
+ * http://www2.ece.rochester.edu/~gsharma/ciede2000/ciede2000noteCRNA.pdf */
+func deltaE2000(c1, c2 color.RGBColor) float64 {
+	l1, a1, b1 := rgbToLab(c1)
+	l2, a2, b2 := rgbToLab(c2)
+	return ciede2000(l1, a1, b1, l2, a2, b2)
+}
+
+
+func ciede2000(l1, a1, b1, l2, a2, b2 float64) float64 {
+	const deg = math.Pi / 180
+	pow7 := func(x float64) float64 { return math.Pow(x, 7) }
+
+	cBar := (math.Hypot(a1, b1) + math.Hypot(a2, b2)) / 2
+	g := 0.5 * (1 - math.Sqrt(pow7(cBar) / (pow7(cBar) + pow7(25))))
+	a1p, a2p := (1 + g) * a1, (1 + g) * a2
+	c1p, c2p := math.Hypot(a1p, b1), math.Hypot(a2p, b2)
+	hp := func(a, b float64) float64 {
+		if a == 0 && b == 0 {
+			return 0
+		}
+		return mod(math.Atan2(b, a) / deg, 360)
+	}
+	h1p, h2p := hp(a1p, b1), hp(a2p, b2)
+
+	dLp := l2 - l1
+	dCp := c2p - c1p
+	var dhp float64
+	if c1p * c2p != 0 {
+		dhp = h2p - h1p
+		if dhp > 180 {
+			dhp -= 360
+		} else if dhp < -180 {
+			dhp += 360
+		}
+	}
+	dHp := 2 * math.Sqrt(c1p * c2p) * math.Sin(dhp / 2 * deg)
+
+	lBarP := (l1 + l2) / 2
+	cBarP := (c1p + c2p) / 2
+	hBarP := h1p + h2p
+	if c1p * c2p != 0 {
+		if math.Abs(h1p - h2p) <= 180 {
+			hBarP /= 2
+		} else if h1p + h2p < 360 {
+			hBarP = (hBarP + 360) / 2
+		} else {
+			hBarP = (hBarP - 360) / 2
+		}
+	}
+
+	t := 1 - 0.17 * math.Cos((hBarP - 30) * deg) + 0.24 * math.Cos(2 * hBarP * deg) +
+		0.32 * math.Cos((3 * hBarP + 6) * deg) - 0.20 * math.Cos((4 * hBarP - 63) * deg)
+	dTheta := 30 * math.Exp(-math.Pow((hBarP - 275) / 25, 2))
+	rc := 2 * math.Sqrt(pow7(cBarP) / (pow7(cBarP) + pow7(25)))
+	sl := 1 + 0.015 * math.Pow(lBarP - 50, 2) / math.Sqrt(20 + math.Pow(lBarP - 50, 2))
+	sc := 1 + 0.045 * cBarP
+	sh := 1 + 0.015 * cBarP * t
+	rt := -math.Sin(2 * dTheta * deg) * rc
+
+	return math.Sqrt(math.Pow(dLp / sl, 2) + math.Pow(dCp / sc, 2) + math.Pow(dHp / sh, 2) + rt * (dCp / sc) * (dHp / sh))
+}
+
+
+/* Inverse of hslToRGB, with hue in degrees and saturation and lightness in
+ * [0, 1]. */
+func rgbToHSL(r, g, b float64) (hue, saturation, lightness float64) {
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	hue = rgbHue(r, g, b)
+	lightness = (max + min) / 2
+	if lightness > 0 && lightness < 1 {
+		saturation = (max - lightness) / math.Min(lightness, 1 - lightness)
+	}
+	return
+}
+
+
+/* Inverse of hsvToRGB, with hue in degrees and saturation and value in [0, 1]. */
+func rgbToHSV(r, g, b float64) (hue, saturation, value float64) {
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	hue = rgbHue(r, g, b)
+	value = max
+	if max > 0 {
+		saturation = (max - min) / max
+	}
+	return
+}
+
+
+/* The hue shared by HSL, HSV and HWB, in degrees. */
+func rgbHue(r, g, b float64) float64 {
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	d := max - min
+	switch {
+	case d == 0:
+		return 0
+	case max == r:
+		return mod((g - b) / d * 60, 360)
+	case max == g:
+		return (b - r) / d * 60 + 120
+	default:
+		return (r - g) / d * 60 + 240
+	}
+}
+
+
+/* Naive (device-dependent) CMYK, with all components in [0, 1]. */
+func rgbToCMYK(r, g, b float64) (c, m, y, k float64) {
+	k = 1 - math.Max(r, math.Max(g, b))
+	if k == 1 {
+		return 0, 0, 0, 1
+	}
+	return (1 - r - k) / (1 - k), (1 - g - k) / (1 - k), (1 - b - k) / (1 - k), k
+}
+
+
+/* Bradford chromatic adaptation from D65 to D50, the inverse of xyzD50ToD65. */
+func xyzD65ToD50(x, y, z float64) (float64, float64, float64) {
+	return  1.0478112 * x + 0.0228866 * y - 0.0501270 * z,
+		 0.0295424 * x + 0.9904844 * y - 0.0170491 * z,
+		-0.0092345 * x + 0.0150436 * y + 0.7521316 * z
+}
+
+
+/* WCAG relative luminance. https://www.w3.org/TR/WCAG21/#dfn-relative-luminance */
+func relativeLuminance(rgbColor color.RGBColor) float64 {
+	r, g, b := rgbToFloats(rgbColor)
+	return 0.2126 * srgbToLinear(r) + 0.7152 * srgbToLinear(g) + 0.0722 * srgbToLinear(b)
+}
+
+
+/* WCAG contrast ratio, from 1 to 21. https://www.w3.org/TR/WCAG21/#dfn-contrast-ratio */
+func contrastRatio(c1, c2 color.RGBColor) float64 {
+	l1, l2 := relativeLuminance(c1), relativeLuminance(c2)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+
+/* Black or white (as a foreground color), whichever is more readable on bg. */
+func readableTextColor(bg color.RGBColor) color.RGBColor {
+	black, white := color.RGB(0, 0, 0), color.RGB(255, 255, 255)
+	if contrastRatio(bg, black) >= contrastRatio(bg, white) {
+		return black
+	}
+	return white
+}
+
+
+/* D65 XYZ to sRGB components, which may be outside [0, 1]. */
+func xyzToSRGB(x, y, z float64) (float64, float64, float64) {
+	r, g, b := xyzToLinearSRGB(x, y, z)
+	return linearToSRGB(r), linearToSRGB(g), linearToSRGB(b)
+}
+
+
+/* CIE xyY chromaticity and luminance to XYZ. */
+func xyYToXYZ(x, y, luminance float64) (float64, float64, float64) {
+	if y == 0 {
+		return 0, 0, 0
+	}
+	return x * luminance / y, luminance, (1 - x - y) * luminance / y
+}
+
+
+/* CIE 1976 u'v' chromaticity and luminance to XYZ. */
+func uvYToXYZ(u, v, luminance float64) (float64, float64, float64) {
+	if v == 0 {
+		return 0, 0, 0
+	}
+	return luminance * 9 * u / (4 * v), luminance, luminance * (12 - 3 * u - 20 * v) / (4 * v)
+}
+
+
+/* The u'v' chromaticity of a white point. */
+func (white whitePoint) uv() (u, v float64) {
+	d := white.x + 15 * white.y + 3 * white.z
+	return 4 * white.x / d, 9 * white.y / d
+}
+
+
+/* CIE L* to relative luminance Y, the inverse of the L* used by Lab and Luv. */
+func lightnessToLuminance(l float64) float64 {
+	if l > 8 {
+		return math.Pow((l + 16) / 116, 3)
+	}
+	return l / (24389.0 / 27)
+}
+
+
+/* CIE L*u*v* relative to the given white point to u'v'Y. */
+func luvToUVY(l, u, v float64, white whitePoint) (uPrime, vPrime, luminance float64) {
+	uWhite, vWhite := white.uv()
+	if l == 0 {
+		return uWhite, vWhite, 0
+	}
+	return u / (13 * l) + uWhite, v / (13 * l) + vWhite, lightnessToLuminance(l)
+}
+
+
+/* Tektronix HVC to u'v'Y, as in Xlib's Xcms: hue in degrees from the best red
+ * as seen from the white point, value is L* and chroma is scaled u'v'
+ * distance from the white point. */
+func tekHVCToUVY(hue, value, chroma float64, white whitePoint) (u, v, luminance float64) {
+	const uBestRed, vBestRed = 0.7127, 0.4931
+	const chromaScale = 7.50725
+
+	uWhite, vWhite := white.uv()
+	if value == 0 {
+		return uWhite, vWhite, 0
+	}
+	thetaOffset := math.Atan2(vBestRed - vWhite, uBestRed - uWhite)
+	angle := hue * math.Pi / 180 + thetaOffset
+	u = uWhite + math.Cos(angle) * chroma / (value * chromaScale)
+	v = vWhite + math.Sin(angle) * chroma / (value * chromaScale)
+	return u, v, lightnessToLuminance(value)
+}
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"flag"
+	"io"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/gookit/color"
@@ -722,32 +726,388 @@ func cleanString(s string) string {
 }
 
 
-func colorNameToRGB(colorName string) (rgbColor color.RGBColor, colorOutputType string, isValid bool) {
-	// TODO: this is stupid, i think this is passed directly to .RGB where it overflows.. yikes
-	rgbColor = color.RGBFromString(colorName, true)
-	isValid = ! rgbColor.IsEmpty()  // TODO FIXME
-	colorOutputType = "rgb"
-	return
+/* Color names are displayed cleaned, everything else (e.g. "rgb(0 0 0 / 50%)")
+ * keeps its whitespace. */
+func displayName(colorName string) string {
+	colorNameClean := cleanString(colorName)
+	for _, table := range nameTables("") {
+		if _, ok := table[colorNameClean]; ok {
+			return colorNameClean
+		}
+	}
+	return strings.TrimSpace(colorName)
 }
 
 
-func colorNameToX11(colorName string) (rgbColor color.RGBColor, colorOutputType string, isValid bool) {
-	rgbColor, isValid = x11Colors[colorName]
-	colorOutputType = "rgb"
+/* Color types to try, in order, when no -type is given.
+ *
+ * CSS comes first, and a color that is recognizably CSS ("#..." or rgb(),
+ * hsl(), hwb(), color() etc.) but invalid is reported as such rather than
+ * tried against the other types.
+ *
+ * RGB, HSL, HSV, Lab, LCH, OKLab and OKLCH share the same bare "a,b,c" syntax,
+ * and RGB comes first, so "210 50 40" is RGB. A bare HSL color is only
+ * detected when it is not valid RGB ("210 50% 40%"); use hsl(...) or -type hsl
+ * otherwise. The others are only auto-detected in functional notation, e.g.
+ * hsv(...) or lab(...).
+ *
+ * Web colors take precedence over X11 colors, so names that differ between the
+ * two (gray, green, maroon, purple) get the value a browser would render. Use
+ * -type x11 or the x11 prefixed names (x11gray) for the X11 values.
+ *
+ * Aliases from the config file and names loaded from names files always come
+ * first, so that they can override built-in names, and the config file may
+ * reorder the rest (see parseTypeOrder).
+ *
+ * An SGR escape sequence ("\e[38;5;208m") is tried first. Only one that sets a
+ * single color is accepted here; showColor displays every color of one.
+ *
+ * X11 color specs ("rgb:ff/80/00", "CIELab:50/20/-30") come before CSS, which
+ * would otherwise take "rgb:" for a broken rgb().
+ *
+ * xterm color indices (ansi256, ansi16) are never auto-detected, since "208"
+ * could as well be part of an RGB triple. */
+var autoColorTypes = []string{"alias", "names", "sgr", "xcolor", "css", "hex", "rgb", "hsl", "hsv", "lab", "lch", "oklab", "oklch", "web", "x11"}
+
+var errUnknownColorType = errors.New("unknown color type")
+
+
+/* Transform a color name of the given type, with an error describing why it
+ * is not a valid color of that type. */
+func transformColor(colorType string, colorName string) (rgbColor color.RGBColor, colorOutputType string, err error) {
+	var isValid bool
+	switch colorType {
+	case "x11":
+		rgbColor, isValid = x11Colors[cleanString(colorName)]
+		colorOutputType = "rgb"
+	case "web":
+		rgbColor, isValid = webColors[cleanString(colorName)]
+		colorOutputType = "rgb"
+	case "css":
+		rgbColor, _, err = parseCSSColor(colorName)
+		if errors.Is(err, errNotCSS) {
+			err = fmt.Errorf("not a valid css color: %q", colorName)
+		}
+		return rgbColor, "rgb", err
+	case "hex":
+		rgbColor, err = parseHex(colorName)
+		return rgbColor, "256", err
+	case "rgb":
+		rgbColor, err = parseRGB(colorName)
+		return rgbColor, "rgb", err
+	case "hsl":
+		rgbColor, err = parseHSL(colorName)
+		return rgbColor, "rgb", err
+	case "hsv", "hsb":
+		rgbColor, err = parseHSV(colorName)
+		return rgbColor, "rgb", err
+	case "lab":
+		rgbColor, err = parseLab(colorName)
+		return rgbColor, "rgb", err
+	case "lch":
+		rgbColor, err = parseLCH(colorName)
+		return rgbColor, "rgb", err
+	case "oklab":
+		rgbColor, err = parseOKLab(colorName)
+		return rgbColor, "rgb", err
+	case "oklch":
+		rgbColor, err = parseOKLCH(colorName)
+		return rgbColor, "rgb", err
+	case "alias":
+		rgbColor, isValid = colorAliases[cleanString(colorName)]
+		colorOutputType = "rgb"
+	case "names":
+		rgbColor, isValid = loadedColors[cleanString(colorName)]
+		colorOutputType = "rgb"
+	case "xcolor":
+		rgbColor, err = parseXColor(colorName)
+		return rgbColor, "rgb", err
+	case "sgr":
+		rgbColor, err = parseSGRColor(colorName)
+		return rgbColor, "rgb", err
+	case "ansi256":
+		rgbColor, err = parseANSI(colorName, 256)
+		return rgbColor, "256", err
+	case "ansi16":
+		rgbColor, err = parseANSI(colorName, 16)
+		return rgbColor, "256", err
+	default:
+		return rgbColor, "", errUnknownColorType
+	}
+	if ! isValid {
+		err = fmt.Errorf("not a valid %s color: %q", colorType, colorName)
+	}
 	return
 }
 
 
-func colorNameToHex(colorName string) (rgbColor color.RGBColor, colorOutputType string, isValid bool) {
-	rgbColor = color.HEX(colorName, true)
-	isValid = ! rgbColor.IsEmpty()
-	colorOutputType = "256"
+/* e.g. "'x11', 'hex'" */
+func quoteList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = "'" + item + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+
+/* An error that colorview exits with. */
+type colorError struct {
+	status  int
+	message string
+}
+
+func (e *colorError) Error() string {
+	return e.message
+}
+
+
+func errorStatus(err error) int {
+	var colorErr *colorError
+	if errors.As(err, &colorErr) {
+		return colorErr.status
+	}
+	return STATUS_INVALID_COLOR
+}
+
+
+/* The result of resolveColor. colorName is the name that was resolved: the
+ * input, or with -fuzzy the known name used in its place. alpha is 1 unless a
+ * CSS color gave one; it is reported, but not displayed. */
+type resolvedColor struct {
+	colorName       string
+	rgbColor        color.RGBColor
+	alpha           float64
+	colorType       string
+	colorOutputType string
+	warning         error
+	sgr             *sgrReport
+}
+
+
+/* Transform a color of the given type, or detect its type (in the order of
+ * autoColorTypes) if colorType is empty. A color that is valid but could not be
+ * displayed exactly is returned with a warning.
+ *
+ * An unknown color name is reported with the closest known names, or with
+ * -fuzzy replaced by the closest one if there is a single best match. */
+func resolveColor(colorType string, colorName string) (resolved resolvedColor, err error) {
+	resolved, err = resolveExactColor(colorType, colorName)
+	if err == nil {
+		return
+	}
+	suggestions, unambiguous := nameSuggestions(colorType, colorName)
+	if len(suggestions) == 0 {
+		return
+	}
+	if fuzzyNames && unambiguous {
+		if fuzzyResolved, fuzzyErr := resolveExactColor(colorType, suggestions[0]); fuzzyErr == nil {
+			fuzzyResolved.warning = fmt.Errorf("unknown color %q, using %q", colorName, suggestions[0])
+			return fuzzyResolved, nil
+		}
+	}
+	return resolved, &colorError{errorStatus(err), fmt.Sprintf("%s (did you mean %s?)", err, orList(suggestions))}
+}
+
+
+func resolveExactColor(colorType string, colorName string) (resolved resolvedColor, err error) {
+	resolved.colorName = colorName
+	resolved.alpha = 1
+	if len(colorType) > 0 {
+		resolved.rgbColor, resolved.colorOutputType, err = transformColor(colorType, colorName)
+		if errors.Is(err, errUnknownColorType) {
+			return resolved, &colorError{STATUS_UNKNOWN_COLORTYPE, "Unknown color type"}
+		}
+	} else {
+		for _, colorType = range autoColorTypes {
+			resolved.rgbColor, resolved.colorOutputType, err = transformColor(colorType, colorName)
+			if err == nil || isOutOfGamut(err) || isDefinitelyInvalid(err) {
+				break
+			}
+		}
+		if err != nil && ! isOutOfGamut(err) && ! isDefinitelyInvalid(err) {
+			/* "300,300,300" is most likely a bad RGB color, not an unknown type */
+			if components, splitErr := splitColorComponents(colorName, "rgb"); splitErr == nil && len(components) == 3 {
+				_, err = parseRGB(colorName)
+				return resolved, &colorError{STATUS_INVALID_COLOR, "Invalid color: " + err.Error()}
+			}
+			return resolved, &colorError{STATUS_UNKNOWN_COLORTYPE, "Could not detect colortype"}
+		}
+	}
+	resolved.colorType = colorType
+	if colorType == "css" && err == nil {
+		/* transformColor keeps only the color */
+		_, resolved.alpha, _ = parseCSSColor(colorName)
+	}
+
+	if isOutOfGamut(err) {
+		resolved.warning = err
+	} else if err != nil {
+		return resolved, &colorError{STATUS_INVALID_COLOR, "Invalid color: " + err.Error()}
+	}
+	return resolved, nil
+}
+
+
+/* Settings from the command line that apply to every color. */
+type options struct {
+	colorType    string
+	outputFormat string
+	as           string
+	info         bool
+	nearest      int
+	contrast     bool
+	swatch       bool
+	fallback     bool
+	swatchOptions
+}
+
+
+/* Display one color, returning the status to exit with: 0 if it was valid. An
+ * error message is prefixed with where, e.g. "line 3", if given. */
+func showColor(colorName string, where string, opts options) (status int) {
+	if (opts.colorType == "" || opts.colorType == "sgr") && isSGR(colorName) {
+		return showSGR(colorName, where, opts)
+	}
+
+	resolved, err := resolveColor(opts.colorType, colorName)
+	if err != nil {
+		reportError(colorName, where, err, opts)
+		return errorStatus(err)
+	}
+	if resolved.warning != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", resolved.warning)
+	}
+	displayColor(colorName, displayName(resolved.colorName), resolved, opts)
+	return 0
+}
+
+
+/* Print err for colorName, in the output format for machine-readable output
+ * and on stderr otherwise. */
+func reportError(colorName string, where string, err error, opts options) {
+	if opts.outputFormat != "text" {
+		printReport(opts.outputFormat, newErrorReport(colorName, err))
+	} else if len(where) > 0 {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", where, err)
+	} else {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+
+/* Display a resolved color as selected by opts, labelled with label in text
+ * output and with colorName in reports. */
+func displayColor(colorName string, label string, resolved resolvedColor, opts options) {
+	rgbColor := resolved.rgbColor
+
+	if opts.outputFormat != "text" {
+		printReport(opts.outputFormat, newColorReport(colorName, resolved))
+		return
+	}
+
+	if len(opts.as) > 0 {
+		label, _ = formatColor(opts.as, rgbColor)
+	}
+
+	if opts.info {
+		printInfo(rgbColor, label, opts.swatchOptions)
+	} else if opts.fallback {
+		printFallbackPreview(rgbColor, label, opts.swatchOptions)
+	} else if opts.swatch {
+		printSwatch(rgbColor, label, opts.swatchOptions)
+	} else if opts.contrast {
+		fmt.Println(paint(label, readableTextColor(rgbColor), rgbColor))
+	} else if color.TermColorLevel() == color.Level256 {
+		printXterm256(rgbColor, label)
+	} else if color.TermColorLevel() == color.Level16 {
+		printANSI16(rgbColor, label)
+	} else {
+		fmt.Println(paint(label, rgbColor))
+	}
+
+	if opts.nearest > 0 {
+		printNearest(rgbColor, opts.nearest)
+	}
+}
+
+
+/* Display text in the foreground color fgName on the background color bgName,
+ * either of which may be empty. With only a background, the text is black or
+ * white, whichever is more readable. In a report format, each color given is
+ * reported instead. */
+func showSample(fgName string, bgName string, text string, opts options) (status int) {
+	if opts.outputFormat == "tsv" {
+		fmt.Println(strings.Join(reportTSVHeader(), "\t"))
+	}
+
+	var fg, bg color.RGBColor
+	for _, c := range []struct {
+		colorName string
+		rgbColor  *color.RGBColor
+	}{
+		{fgName, &fg},
+		{bgName, &bg},
+	} {
+		if len(c.colorName) == 0 {
+			continue
+		}
+		resolved, err := resolveColor(opts.colorType, c.colorName)
+		if err != nil {
+			reportError(c.colorName, "", err, opts)
+			return errorStatus(err)
+		}
+		if opts.outputFormat != "text" {
+			printReport(opts.outputFormat, newColorReport(c.colorName, resolved))
+			continue
+		}
+		if resolved.warning != nil {
+			fmt.Fprintln(os.Stderr, "Warning:", resolved.warning)
+		}
+		*c.rgbColor = resolved.rgbColor
+	}
+	if opts.outputFormat != "text" {
+		return 0
+	}
+
+	switch {
+	case len(fgName) == 0:
+		fmt.Println(paint(text, readableTextColor(bg), bg))
+	case len(bgName) == 0:
+		fmt.Println(paint(text, color.RGB(fg[0], fg[1], fg[2])))
+	default:
+		fmt.Println(paint(text, color.RGB(fg[0], fg[1], fg[2]), bg))
+		fmt.Printf("contrast %.2f:1\n", contrastRatio(fg, bg))
+	}
+	return 0
+}
+
+
+/* Display every non-blank line of r as a color, returning the status of the
+ * first invalid one. */
+func showColorsFromReader(r io.Reader, opts options) (status int) {
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		colorName := strings.TrimSpace(scanner.Text())
+		if len(colorName) == 0 {
+			continue
+		}
+		if lineStatus := showColor(colorName, fmt.Sprintf("line %d", lineNumber), opts); status == 0 {
+			status = lineStatus
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if status == 0 {
+			status = STATUS_INVALID_COLOR
+		}
+	}
 	return
 }
 
 
 func dieImmediate(status int, message... string) {
-	fmt.Fprintln(os.Stderr, message)
+	fmt.Fprintln(os.Stderr, strings.Join(message, " "))
 	os.Exit(status)
 }
 
@@ -773,17 +1133,35 @@ func main() {
 	 *     --hsl
 	 *     --lab
 	 *     etc...
-	 *   Otherwise, search in this order:
-	 *     1. hex
-	 *     3. rgb
-	 *     2. x11
+	 *   Otherwise, search in the order of autoColorTypes
 	 *
 	 * X11 and web colors can be written in any case and with any whitespace - they will be "cleaned" to lower-case and no whitespace
 	 */
 
-	var colorName, colorNameClean, colorType string
+	var colorName, colorType, outputFormat string
 
-	var colorTypeFlag = flag.String("type", "", "Color type. Must be one of: 'x11', 'hex', 'rgb'.")
+	var colorTypeFlag = flag.String("type", "", "Color type. Must be one of: 'x11', 'web', 'css', 'hex', 'rgb', 'hsl', 'hsv', 'lab', 'lch', 'oklab', 'oklch', 'ansi256', 'ansi16', 'sgr', 'xcolor', 'alias', 'names'.")
+	var whitePointFlag = flag.String("whitepoint", "d65", "White point for Lab colors. Must be one of: 'd65', 'd50'.")
+	var asFlag = flag.String("as", "", "Print the color in this notation instead of its name. Must be one of: " + quoteList(colorNotations) + ".")
+	var infoFlag = flag.Bool("info", false, "Print every representation of the color next to a swatch.")
+	var nearestFlag = flag.Int("nearest", 0, "Also print the N closest named colors (by CIEDE2000).")
+	var formatFlag = flag.String("format", "text", "Output format. Must be one of: 'text', " + quoteList(reportFormats) + ".")
+	var fgFlag = flag.String("fg", "", "Foreground color for a text sample. The remaining arguments are the text.")
+	var bgFlag = flag.String("bg", "", "Background color for a text sample. The remaining arguments are the text.")
+	var contrastFlag = flag.Bool("contrast", false, "Show colors with black or white text, whichever is more readable.")
+	var fallbackFlag = flag.Bool("fallback", false, "Show truecolor, xterm 256 color and ANSI 16 color swatches side by side.")
+	var swatchFlag = flag.Bool("swatch", false, "Show colors as swatches instead of coloring their names.")
+	var swatchWidthFlag = flag.Int("swatch-width", 8, "Swatch width, in columns.")
+	var swatchHeightFlag = flag.Int("swatch-height", 1, "Swatch height, in lines (half lines for 'halfblocks').")
+	var swatchStyleFlag = flag.String("swatch-style", "cells", "Swatch glyphs. Must be one of: " + quoteList(swatchStyles) + ".")
+	var labelFlag = flag.String("label", "beside", "Where to put the label of a swatch. Must be one of: " + quoteList(labelPlacements) + ".")
+	var highlightFlag = flag.Bool("highlight", false, "Copy text from stdin or the given files, painting each color literal in its color.")
+	var onlyMatchingFlag = flag.Bool("only-matching", false, "With -highlight, print only the color literals, with their line numbers.")
+	var fuzzyFlag = flag.Bool("fuzzy", false, "Use the closest color name for an unknown name, if there is a single best match.")
+	var namesFiles stringList
+	flag.Var(&namesFiles, "names-file", "Load color names from this file (X11 rgb.txt or 'name = value' lines). May be repeated. Default: " + defaultNamesFile() + ", if it exists.")
+	var colorFlag = flag.String("color", "auto", "When to use color, or which color level to use. Must be one of: " + quoteList(colorModes) + ".")
+	var configFlag = flag.String("config", "", "Read defaults for flags, the detection order and color aliases from this file. Default: " + defaultConfigFile() + ", if it exists.")
 	var versionFlag = flag.Bool("version", false, "Print version and exit.")
 	//flag_x11 = flag.Bool("x11", false, "Use X11 colors")
 	//flag_web = flag.Bool("web", false, "Use web colors")
@@ -795,17 +1173,85 @@ func main() {
 
 	flag.Parse()
 
-	colorName = flag.Arg(0)
-	if len(colorName) == 0 {
-		dieImmediate(STATUS_INVALID_COLOR, "Color name is required")
-	}
-
 	if *versionFlag {
 		printVersion()
 		os.Exit(0)
 	}
 
-	// TODO: --fg and --bg options
+	configFile := *configFlag
+	if len(configFile) == 0 {
+		configFile = defaultConfigFile()
+	}
+	cfg, problems, err := readConfigFile(configFile, len(*configFlag) > 0)
+	if err != nil {
+		dieImmediate(STATUS_INVALID_COLOR, err.Error())
+	}
+	onCommandLine := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		onCommandLine[f.Name] = true
+	})
+	typeOrder, settingsProblems := cfg.applySettings(flag.CommandLine, onCommandLine)
+	problems = append(problems, settingsProblems...)
+	for _, problem := range problems {
+		fmt.Fprintln(os.Stderr, "Warning:", problem)
+	}
+
+	if err := setColorMode(cleanString(*colorFlag)); err != nil {
+		dieImmediate(STATUS_UNKNOWN_COLORTYPE, err.Error())
+	}
+
+	// Names and aliases may be defined with Lab colors, so the white point is
+	// needed before they are loaded.
+	switch cleanString(*whitePointFlag) {
+	case "d65":
+		labWhitePoint = whiteD65
+	case "d50":
+		labWhitePoint = whiteD50
+	default:
+		dieImmediate(STATUS_INVALID_COLOR, "Unknown white point:", *whitePointFlag)
+	}
+
+	fuzzyNames = *fuzzyFlag
+
+	if len(namesFiles) == 0 {
+		if fileName := defaultNamesFile(); len(fileName) > 0 {
+			if _, err := os.Stat(fileName); err == nil {
+				namesFiles = stringList{fileName}
+			}
+		}
+	}
+	namesProblems, err := loadNamesFiles(namesFiles)
+	for _, problem := range namesProblems {
+		fmt.Fprintln(os.Stderr, "Warning:", problem)
+	}
+	if err != nil {
+		dieImmediate(STATUS_INVALID_COLOR, err.Error())
+	}
+	for _, problem := range cfg.defineAliases() {
+		fmt.Fprintln(os.Stderr, "Warning:", problem)
+	}
+	if typeOrder != nil {
+		autoColorTypes = typeOrder
+	}
+
+	colorNames := flag.Args()
+	if *highlightFlag {
+		os.Exit(highlightFiles(colorNames, *onlyMatchingFlag))
+	}
+	if len(colorNames) > 0 && colorNames[0] == "list" {
+		os.Exit(listColors(colorNames[1:]))
+	}
+	if len(*fgFlag) == 0 && len(*bgFlag) == 0 && len(colorNames) == 0 {
+		if stat, err := os.Stdin.Stat(); err != nil || stat.Mode() & os.ModeCharDevice != 0 {
+			dieImmediate(STATUS_INVALID_COLOR, "Color name is required")
+		}
+		colorNames = []string{"-"}
+	}
+
+	outputFormat = cleanString(*formatFlag)
+	if outputFormat != "text" && ! containsString(reportFormats, outputFormat) {
+		dieImmediate(STATUS_UNKNOWN_COLORTYPE, "Unknown output format:", *formatFlag)
+	}
 
 	// TODO: use iota instead of magic strings? see https://stackoverflow.com/q/14426366
 
@@ -814,64 +1260,73 @@ func main() {
 	}
 
 	colorType = cleanString(colorType)
-	colorNameClean = cleanString(colorName)
 
 	//fmt.Println("colorType", colorType)
-	//fmt.Println("colorName", colorName)
-	//fmt.Println("colorName", colorNameClean)
+	//fmt.Println("colorNames", colorNames)
 
 	//fmt.Println("Bad rgb (overflow)", color.RGBFromString("300,300,300"))
 	//fmt.Println("Bad rgb (invalid)", color.RGBFromString("oogey"))
 	//fmt.Println("Bad rgb (both)", color.RGBFromString("300,300,asdfsaf"))
 	//fmt.Println("Bad hex", color.HEX("oogabooga"))
 
-	var rgbColor        color.RGBColor
-	var colorOutputType string
-	var isValid         bool
+	opts := options{
+		colorType:    colorType,
+		outputFormat: outputFormat,
+		as:           cleanString(*asFlag),
+		info:         *infoFlag,
+		nearest:      *nearestFlag,
+		contrast:     *contrastFlag,
+		swatch:       *swatchFlag,
+		fallback:     *fallbackFlag,
+		swatchOptions: swatchOptions{
+			width:  *swatchWidthFlag,
+			height: *swatchHeightFlag,
+			style:  cleanString(*swatchStyleFlag),
+			label:  cleanString(*labelFlag),
+		},
+	}
+	if len(opts.as) > 0 && ! containsString(colorNotations, opts.as) {
+		dieImmediate(STATUS_UNKNOWN_COLORTYPE, "Invalid -as:", *asFlag)
+	}
 
-	if len(colorType) > 0 {
-		switch {
-		case colorType == "x11":
-			rgbColor, colorOutputType, isValid = colorNameToX11(colorNameClean)
-		case colorType == "web":
-			dieImmediate(STATUS_NOT_IMPLEMENTED, "Web color not implemented")
-		case colorType == "hex":
-			rgbColor, colorOutputType, isValid = colorNameToHex(colorNameClean)
-		case colorType == "rgb":
-			rgbColor, colorOutputType, isValid = colorNameToRGB(colorNameClean)
-		case colorType == "hsv":
-			dieImmediate(STATUS_NOT_IMPLEMENTED, "HSV color not implemented")
-		case colorType == "hsl":
-			dieImmediate(STATUS_NOT_IMPLEMENTED, "HSL color not implemented")
-		case colorType == "lab":
-			dieImmediate(STATUS_NOT_IMPLEMENTED, "LAB color not implemented")
-		default:
-			dieImmediate(STATUS_UNKNOWN_COLORTYPE, "Unknown color type")
-		}
-	} else {
-		colorTransformers := [](func(string) (color.RGBColor, string, bool)) {colorNameToHex, colorNameToRGB, colorNameToX11}
-		for _, transformer := range colorTransformers {
-			rgbColor, colorOutputType, isValid = transformer(colorNameClean)
-			//fmt.Println("rgbColor", rgbColor)
-			//fmt.Println("colorOutputType", colorOutputType)
-			//fmt.Println("isValid", isValid)
-			if isValid {
-				break
-			}
-		}
-		if ! isValid {
-			dieImmediate(STATUS_UNKNOWN_COLORTYPE, "Could not detect colortype")
+	if len(*fgFlag) > 0 || len(*bgFlag) > 0 {
+		text := strings.Join(colorNames, " ")
+		if len(text) == 0 {
+			text = "The quick brown fox jumps over the lazy dog"
 		}
+		os.Exit(showSample(*fgFlag, *bgFlag, text, opts))
 	}
 
-	if ! isValid {
-		dieImmediate(STATUS_INVALID_COLOR, "Invalid color:", colorName)
+	if ! containsString(swatchStyles, opts.swatchOptions.style) {
+		dieImmediate(STATUS_UNKNOWN_COLORTYPE, "Unknown swatch style:", *swatchStyleFlag)
+	}
+	if ! containsString(labelPlacements, opts.swatchOptions.label) {
+		dieImmediate(STATUS_UNKNOWN_COLORTYPE, "Unknown label placement:", *labelFlag)
+	}
+	if opts.swatchOptions.width < 1 {
+		dieImmediate(STATUS_INVALID_COLOR, "Swatch width must be at least 1:", strconv.Itoa(*swatchWidthFlag))
+	}
+	if opts.swatchOptions.height < 1 {
+		dieImmediate(STATUS_INVALID_COLOR, "Swatch height must be at least 1:", strconv.Itoa(*swatchHeightFlag))
 	}
 
-	if colorOutputType == "256" {
-		// rgbToC256(rgbColor).Print(colorNameClean)
-		rgbColor.Println(colorNameClean)
-	} else {
-		rgbColor.Println(colorNameClean)
+	if outputFormat == "tsv" {
+		fmt.Println(strings.Join(reportTSVHeader(), "\t"))
+	}
+
+	exitStatus := 0
+	fail := func(status int) {
+		if exitStatus == 0 {
+			exitStatus = status
+		}
+	}
+	for _, colorName = range colorNames {
+		if colorName == "-" {
+			fail(showColorsFromReader(os.Stdin, opts))
+		} else {
+			fail(showColor(colorName, "", opts))
+		}
 	}
+	os.Exit(exitStatus)
+
 }
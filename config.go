@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+
+/* Flags that may be given defaults in the config file, under the same name. */
+var configurableFlags = []string{"format", "color", "whitepoint", "swatch", "swatch-width", "swatch-height", "swatch-style", "label", "fuzzy", "names-file"}
+
+
+/* Personal color names from the [aliases] section of the config file. They
+ * are tried before any other color type. */
+var colorAliases = map[string]color.RGBColor{}
+
+
+/* A "key = value" line of the config file. */
+type configEntry struct {
+	line  int
+	key   string
+	value string
+}
+
+
+/* A config file:
+ *
+ *   # defaults for flags, by flag name
+ *   format = json
+ *   swatch-width = 12
+ *   # the order types are detected in
+ *   order = hex, x11, web
+ *
+ *   [aliases]
+ *   brand-primary = #0055aa */
+type config struct {
+	fileName string
+	settings []configEntry
+	aliases  []configEntry
+}
+
+
+func defaultConfigFile() string {
+	if dir := configDir(); len(dir) > 0 {
+		return filepath.Join(dir, "config")
+	}
+	return ""
+}
+
+
+/* Read the config file fileName. A missing file is an empty config unless
+ * required is set. */
+func readConfigFile(fileName string, required bool) (cfg config, problems []error, err error) {
+	cfg.fileName = fileName
+	f, err := os.Open(fileName)
+	if errors.Is(err, os.ErrNotExist) && ! required {
+		return cfg, nil, nil
+	} else if err != nil {
+		return
+	}
+	defer f.Close()
+	cfg, problems = readConfig(f, fileName)
+	return
+}
+
+
+func readConfig(r io.Reader, fileName string) (cfg config, problems []error) {
+	cfg.fileName = fileName
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = cleanString(line[1 : len(line)-1])
+			if section != "aliases" {
+				problems = append(problems, fmt.Errorf("%s:%d: unknown section %q", fileName, lineNumber, line))
+			}
+			continue
+		}
+
+		i := strings.IndexByte(line, '=')
+		if i < 0 {
+			problems = append(problems, fmt.Errorf("%s:%d: expected \"key = value\", got %q", fileName, lineNumber, line))
+			continue
+		}
+		entry := configEntry{lineNumber, strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])}
+		switch section {
+		case "":
+			cfg.settings = append(cfg.settings, entry)
+		case "aliases":
+			cfg.aliases = append(cfg.aliases, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		problems = append(problems, fmt.Errorf("%s: %s", fileName, err))
+	}
+	return
+}
+
+
+/* Set the flags named in the config file, except those given on the command
+ * line. Returns the order for autoColorTypes, if the file sets one; it is left
+ * to the caller so that names and aliases can be resolved with every type. */
+func (cfg config) applySettings(flags *flag.FlagSet, onCommandLine map[string]bool) (order []string, problems []error) {
+	for _, entry := range cfg.settings {
+		problem := func(format string, a ...interface{}) {
+			problems = append(problems, fmt.Errorf("%s:%d: %s", cfg.fileName, entry.line, fmt.Sprintf(format, a...)))
+		}
+
+		key := cleanString(entry.key)
+		switch {
+		case key == "order":
+			typeOrder, err := parseTypeOrder(entry.value)
+			if err != nil {
+				problem("%s", err)
+				continue
+			}
+			order = typeOrder
+		case ! containsString(configurableFlags, key):
+			problem("unknown setting %q", entry.key)
+		case onCommandLine[key]:
+			continue
+		default:
+			if err := flags.Set(key, entry.value); err != nil {
+				problem("invalid %s %q: %s", key, entry.value, err)
+			}
+		}
+	}
+	return
+}
+
+
+/* A comma or space separated list of color types, for autoColorTypes. Aliases
+ * and loaded names always come first. */
+func parseTypeOrder(value string) (order []string, err error) {
+	order = []string{"alias", "names"}
+	for _, colorType := range strings.Fields(strings.ReplaceAll(value, ",", " ")) {
+		colorType = strings.ToLower(colorType)
+		if _, _, typeErr := transformColor(colorType, ""); errors.Is(typeErr, errUnknownColorType) {
+			return nil, fmt.Errorf("unknown color type %q in order", colorType)
+		}
+		if ! containsString(order, colorType) {
+			order = append(order, colorType)
+		}
+	}
+	if len(order) == 2 {
+		return nil, fmt.Errorf("order lists no color types")
+	}
+	return
+}
+
+
+/* Define the aliases in the config file. Their values may use any color name,
+ * including loaded names and aliases defined before them. */
+func (cfg config) defineAliases() (problems []error) {
+	definedOn := map[string]int{}
+	for _, entry := range cfg.aliases {
+		name, rgbColor, err := parseNameAssignment(entry.key, entry.value)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s:%d: %s", cfg.fileName, entry.line, err))
+			continue
+		}
+		key := cleanString(name)
+		if first, ok := definedOn[key]; ok {
+			problems = append(problems, fmt.Errorf("%s:%d: duplicate alias %q, first defined on line %d", cfg.fileName, entry.line, name, first))
+		} else {
+			definedOn[key] = entry.line
+		}
+		colorAliases[key] = rgbColor
+	}
+	return
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+
+func TestReadConfig(t *testing.T) {
+	cfg, problems := readConfig(strings.NewReader(`# defaults
+format = json
+; comment
+swatch-width=12
+
+[aliases]
+brand-primary = #0055aa
+no equals sign
+[colors]
+`), "config")
+
+	entries := func(entries []configEntry) string {
+		var s []string
+		for _, entry := range entries {
+			s = append(s, fmt.Sprintf("%d:%s=%s", entry.line, entry.key, entry.value))
+		}
+		return strings.Join(s, " ")
+	}
+	if got := entries(cfg.settings); got != "2:format=json 4:swatch-width=12" {
+		t.Errorf("settings = %q", got)
+	}
+	if got := entries(cfg.aliases); got != "7:brand-primary=#0055aa" {
+		t.Errorf("aliases = %q", got)
+	}
+
+	want := []string{"config:8: ", `config:9: unknown section "[colors]"`}
+	if len(problems) != len(want) {
+		t.Fatalf("got problems %q, want %d", problems, len(want))
+	}
+	for i, problem := range problems {
+		if ! strings.HasPrefix(problem.Error(), want[i]) {
+			t.Errorf("problem %d = %q, want it to start with %q", i, problem, want[i])
+		}
+	}
+}
+
+
+func TestParseTypeOrder(t *testing.T) {
+	for _, c := range []struct {
+		value string
+		order string
+	}{
+		{"hex, x11, web", "alias names hex x11 web"},
+		{"HEX x11,web", "alias names hex x11 web"},
+		{"hex, hex, rgb", "alias names hex rgb"},
+		{"alias, hex", "alias names hex"},
+		{"hex, nosuchtype", ""},
+		{"", ""},
+	} {
+		order, err := parseTypeOrder(c.value)
+		switch {
+		case len(c.order) == 0 && err == nil:
+			t.Errorf("parseTypeOrder(%q) = %q, want an error", c.value, order)
+		case len(c.order) > 0 && err != nil:
+			t.Errorf("parseTypeOrder(%q) failed: %s", c.value, err)
+		case len(c.order) > 0 && strings.Join(order, " ") != c.order:
+			t.Errorf("parseTypeOrder(%q) = %q, want %q", c.value, order, c.order)
+		}
+	}
+}
+
+
+/* Settings are applied unless the flag was given on the command line. */
+func TestApplySettings(t *testing.T) {
+	before := strings.Join(autoColorTypes, " ")
+	flags := flag.NewFlagSet("colorview", flag.ContinueOnError)
+	format := flags.String("format", "text", "")
+	swatchWidth := flags.Int("swatch-width", 8, "")
+	cfg, _ := readConfig(strings.NewReader(`format = json
+swatch-width = 12
+order = hex, web
+swatch-height = x
+unknown = 1
+`), "config")
+
+	order, problems := cfg.applySettings(flags, map[string]bool{"swatch-width": true})
+	if *format != "json" {
+		t.Errorf("format = %q, want json", *format)
+	}
+	if *swatchWidth != 8 {
+		t.Errorf("swatch-width = %d, want 8 from the command line", *swatchWidth)
+	}
+	if got := strings.Join(order, " "); got != "alias names hex web" {
+		t.Errorf("order = %q", got)
+	}
+	if got := strings.Join(autoColorTypes, " "); got != before {
+		t.Errorf("applySettings changed autoColorTypes to %q", got)
+	}
+	if len(problems) != 2 {
+		t.Errorf("got problems %q, want 2", problems)
+	}
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+
+/* https://www.w3.org/TR/css-color-4/
+ *
+ * Supported:
+ *   #rgb, #rgba, #rrggbb, #rrggbbaa
+ *   rgb(), rgba(), hsl(), hsla(), hwb() with commas or spaces, "/ alpha" and "none"
+ *   color(srgb | srgb-linear | display-p3 | xyz | xyz-d50 | xyz-d65 ...)
+ *   named colors, including "transparent"
+ *
+ * lab(), lch(), oklab() and oklch() are handled by the Lab, LCH, OKLab and
+ * OKLCH color types.
+ */
+
+
+/* Returned when a color is recognizably written in CSS syntax (a "#" hex color
+ * or one of the CSS color functions) but is not valid. */
+type cssParseError struct {
+	colorName string
+	message   string
+}
+
+func (e *cssParseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.colorName, e.message)
+}
+
+
+func isCSSParseError(err error) bool {
+	var cssErr *cssParseError
+	return errors.As(err, &cssErr)
+}
+
+
+/* Returned when a color is not written in CSS syntax at all, so another color
+ * type should be tried. */
+var errNotCSS = errors.New("not a CSS color")
+
+
+var cssFunction = regexp.MustCompile(`^([a-z-]+)\((.*)\)$`)
+
+
+/* Parse a CSS color, returning its alpha in [0, 1] separately. */
+func parseCSSColor(colorName string) (rgbColor color.RGBColor, alpha float64, err error) {
+	s := strings.ToLower(strings.TrimSpace(colorName))
+	fail := func(format string, args ...interface{}) (color.RGBColor, float64, error) {
+		return rgbColor, 0, &cssParseError{colorName, fmt.Sprintf(format, args...)}
+	}
+
+	if strings.HasPrefix(s, "#") {
+		r, g, b, a, ok := parseCSSHex(s[1:])
+		if ! ok {
+			return fail("hex colors must have 3, 4, 6 or 8 hex digits")
+		}
+		return rgbFromFloats(r, g, b), a, nil
+	}
+
+	if rgbColor, ok := webColors[s]; ok {
+		if s == "transparent" {
+			return rgbColor, 0, nil
+		}
+		return rgbColor, 1, nil
+	}
+
+	match := cssFunction.FindStringSubmatch(s)
+	if match == nil {
+		if open := strings.IndexByte(s, '('); open > 0 && isCSSFunctionName(s[:open]) {
+			return fail("missing closing parenthesis")
+		}
+		return rgbColor, 0, errNotCSS
+	}
+	funcName := match[1]
+	if ! isCSSFunctionName(funcName) {
+		return rgbColor, 0, errNotCSS
+	}
+
+	components, alphaString, legacy, splitErr := splitCSSArguments(match[2])
+	if splitErr != nil {
+		return fail("%s(): %s", funcName, splitErr)
+	}
+
+	alpha = 1
+	if len(alphaString) > 0 {
+		alpha, err = parseCSSAlpha(alphaString)
+		if err != nil {
+			return fail("%s(): %s", funcName, err)
+		}
+	}
+
+	var r, g, b float64
+	switch funcName {
+	case "rgb", "rgba":
+		r, g, b, err = parseCSSRGB(components, legacy)
+	case "hsl", "hsla":
+		r, g, b, err = parseCSSHSL(components, legacy)
+	case "hwb":
+		if legacy {
+			err = errors.New("hwb() does not accept commas")
+		} else {
+			r, g, b, err = parseCSSHWB(components)
+		}
+	case "color":
+		if legacy {
+			err = errors.New("color() does not accept commas")
+		} else {
+			r, g, b, err = parseCSSColorFunction(components)
+		}
+	}
+	if err != nil {
+		return fail("%s(): %s", funcName, err)
+	}
+
+	rgbColor = rgbFromFloats(r, g, b)
+	if ! inSRGBGamut(r, g, b) {
+		err = &outOfGamutError{colorName, rgbColor}
+	}
+	return rgbColor, alpha, err
+}
+
+
+func isCSSFunctionName(funcName string) bool {
+	return containsString([]string{"rgb", "rgba", "hsl", "hsla", "hwb", "color"}, strings.TrimSpace(funcName))
+}
+
+
+func parseCSSHex(hex string) (r, g, b, a float64, ok bool) {
+	if _, err := strconv.ParseUint(hex, 16, 64); err != nil {
+		return
+	}
+	digits := 2
+	switch len(hex) {
+	case 3, 4:
+		digits = 1
+	case 6, 8:
+	default:
+		return
+	}
+
+	values := []float64{1, 1, 1, 1}
+	for i := 0; i * digits < len(hex); i++ {
+		n, _ := strconv.ParseUint(hex[i * digits : (i + 1) * digits], 16, 8)
+		if digits == 1 {
+			n *= 17
+		}
+		values[i] = float64(n) / 255
+	}
+	return values[0], values[1], values[2], values[3], true
+}
+
+
+/* Split the arguments of a CSS color function into its components and alpha.
+ * Legacy syntax separates every argument with commas ("1, 2, 3, 0.5"), modern
+ * syntax uses spaces and a slash before the alpha ("1 2 3 / 50%"). */
+func splitCSSArguments(args string) (components []string, alpha string, legacy bool, err error) {
+	if strings.Contains(args, ",") {
+		if strings.Contains(args, "/") {
+			return nil, "", true, errors.New("cannot mix commas and \"/\"")
+		}
+		for _, component := range strings.Split(args, ",") {
+			component = strings.TrimSpace(component)
+			if len(component) == 0 || strings.ContainsAny(component, " \t") {
+				return nil, "", true, fmt.Errorf("invalid argument list %q", args)
+			}
+			components = append(components, component)
+		}
+		if len(components) == 4 {
+			components, alpha = components[:3], components[3]
+		}
+		return components, alpha, true, nil
+	}
+
+	parts := strings.Split(args, "/")
+	if len(parts) > 2 {
+		return nil, "", false, errors.New("more than one \"/\"")
+	}
+	components = strings.Fields(parts[0])
+	if len(parts) == 2 {
+		alphaFields := strings.Fields(parts[1])
+		if len(alphaFields) != 1 {
+			return nil, "", false, errors.New("expected a single alpha value after \"/\"")
+		}
+		alpha = alphaFields[0]
+	}
+	return components, alpha, false, nil
+}
+
+
+/* Parse a CSS <number> or <percentage>, with "none" as zero. A percentage is
+ * scaled so that 100% is percentScale. */
+func parseCSSNumber(s string, componentName string, percentScale float64) (n float64, isPercent bool, err error) {
+	if s == "none" {
+		return 0, false, nil
+	}
+	isPercent = strings.HasSuffix(s, "%")
+	n, err = strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, isPercent, fmt.Errorf("invalid %s %q", componentName, s)
+	}
+	if isPercent {
+		n = n / 100 * percentScale
+	}
+	return n, isPercent, nil
+}
+
+
+func parseCSSAlpha(s string) (alpha float64, err error) {
+	alpha, _, err = parseCSSNumber(s, "alpha", 1)
+	return clamp01(alpha), err
+}
+
+
+func expectComponents(components []string, n int) error {
+	if len(components) != n {
+		return fmt.Errorf("expected %d components, got %d", n, len(components))
+	}
+	return nil
+}
+
+
+/* https://www.w3.org/TR/css-color-4/#rgb-functions
+ * Out-of-range values are clamped, like browsers do. */
+func parseCSSRGB(components []string, legacy bool) (r, g, b float64, err error) {
+	if err = expectComponents(components, 3); err != nil {
+		return
+	}
+	var values [3]float64
+	var percents int
+	for i, name := range [3]string{"red", "green", "blue"} {
+		var isPercent bool
+		values[i], isPercent, err = parseCSSNumber(components[i], name, 255)
+		if err != nil {
+			return
+		}
+		if isPercent {
+			percents++
+		}
+	}
+	if legacy && percents != 0 && percents != 3 {
+		err = errors.New("cannot mix numbers and percentages with commas")
+		return
+	}
+	return clamp01(values[0] / 255), clamp01(values[1] / 255), clamp01(values[2] / 255), nil
+}
+
+
+/* https://www.w3.org/TR/css-color-4/#the-hsl-notation */
+func parseCSSHSL(components []string, legacy bool) (r, g, b float64, err error) {
+	if err = expectComponents(components, 3); err != nil {
+		return
+	}
+	hue, err := parseCSSHue(components[0])
+	if err != nil {
+		return
+	}
+	var sl [2]float64
+	for i, name := range [2]string{"saturation", "lightness"} {
+		var isPercent bool
+		sl[i], isPercent, err = parseCSSNumber(components[i+1], name, 100)
+		if err != nil {
+			return
+		}
+		if legacy && ! isPercent {
+			err = fmt.Errorf("%s %q must be a percentage", name, components[i+1])
+			return
+		}
+		sl[i] = clamp01(sl[i] / 100)
+	}
+	r, g, b = hslToRGB(hue, sl[0], sl[1])
+	return
+}
+
+
+/* https://www.w3.org/TR/css-color-4/#the-hwb-notation */
+func parseCSSHWB(components []string) (r, g, b float64, err error) {
+	if err = expectComponents(components, 3); err != nil {
+		return
+	}
+	hue, err := parseCSSHue(components[0])
+	if err != nil {
+		return
+	}
+	var wb [2]float64
+	for i, name := range [2]string{"whiteness", "blackness"} {
+		wb[i], _, err = parseCSSNumber(components[i+1], name, 100)
+		if err != nil {
+			return
+		}
+		wb[i] = clamp01(wb[i] / 100)
+	}
+	r, g, b = hwbToRGB(hue, wb[0], wb[1])
+	return
+}
+
+
+func parseCSSHue(s string) (hue float64, err error) {
+	if s == "none" {
+		return 0, nil
+	}
+	return parseHue(s)
+}
+
+
+/* https://www.w3.org/TR/css-color-4/#color-function */
+func parseCSSColorFunction(components []string) (r, g, b float64, err error) {
+	if len(components) == 0 {
+		err = errors.New("missing color space")
+		return
+	}
+	colorSpace := components[0]
+	if err = expectComponents(components[1:], 3); err != nil {
+		return
+	}
+	var values [3]float64
+	for i := range values {
+		values[i], _, err = parseCSSNumber(components[i+1], fmt.Sprintf("%s component", colorSpace), 1)
+		if err != nil {
+			return
+		}
+	}
+
+	x, y, z := values[0], values[1], values[2]
+	switch colorSpace {
+	case "srgb":
+		return x, y, z, nil
+	case "srgb-linear":
+		r, g, b = x, y, z
+	case "display-p3":
+		r, g, b = xyzToLinearSRGB(linearP3ToXYZ(srgbToLinear(x), srgbToLinear(y), srgbToLinear(z)))
+	case "xyz", "xyz-d65":
+		r, g, b = xyzToLinearSRGB(x, y, z)
+	case "xyz-d50":
+		r, g, b = xyzToLinearSRGB(xyzD50ToD65(x, y, z))
+	default:
+		err = fmt.Errorf("unsupported color space %q", colorSpace)
+		return
+	}
+	return linearToSRGB(r), linearToSRGB(g), linearToSRGB(b), nil
+}
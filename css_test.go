@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+
+func TestParseCSSColor(t *testing.T) {
+	for _, c := range []struct {
+		colorName string
+		hex       string
+		alpha     float64
+	}{
+		{"#f80", "ff8800", 1},
+		{"#ff8800", "ff8800", 1},
+		{"#ff880080", "ff8800", 128.0 / 255},
+		{"rgb(255 128 0)", "ff8000", 1},
+		{"rgb(255,128,0)", "ff8000", 1},
+		{"rgba(255 128 0 / 50%)", "ff8000", 0.5},
+		{"rgb(300 0 0)", "ff0000", 1},
+		{"hsl(120 100% 50%)", "00ff00", 1},
+		{"hsl(none 0% 50%)", "808080", 1},
+		{"hwb(0 0% 0%)", "ff0000", 1},
+		{"color(srgb 1 0.5 0)", "ff8000", 1},
+		{"RebeccaPurple", "663399", 1},
+		{"transparent", "000000", 0},
+	} {
+		rgbColor, alpha, err := parseCSSColor(c.colorName)
+		if err != nil {
+			t.Errorf("parseCSSColor(%q) failed: %s", c.colorName, err)
+			continue
+		}
+		if rgbColor.Hex() != c.hex || alpha != c.alpha {
+			t.Errorf("parseCSSColor(%q) = #%s alpha %g, want #%s alpha %g", c.colorName, rgbColor.Hex(), alpha, c.hex, c.alpha)
+		}
+	}
+}
+
+
+/* Broken CSS is a *cssParseError, which stops auto-detection; anything else is
+ * errNotCSS. */
+func TestParseCSSColorErrors(t *testing.T) {
+	for _, colorName := range []string{"#ggg", "#12345", "rgb(1 2)", "hsl(0 x 50%)"} {
+		if _, _, err := parseCSSColor(colorName); ! isCSSParseError(err) {
+			t.Errorf("parseCSSColor(%q): got error %v, want a CSS parse error", colorName, err)
+		}
+	}
+	for _, colorName := range []string{"255,128,0", "notacolor", "rgb:ff/80/00"} {
+		if _, _, err := parseCSSColor(colorName); err != errNotCSS {
+			t.Errorf("parseCSSColor(%q): got error %v, want errNotCSS", colorName, err)
+		}
+	}
+}
+
+
+/* A CSS color's alpha is kept for reports; other colors are opaque. */
+func TestResolveColorAlpha(t *testing.T) {
+	for _, c := range []struct {
+		colorName string
+		alpha     float64
+	}{
+		{"#ff800080", 0.502},
+		{"rgb(255 128 0 / 25%)", 0.25},
+		{"transparent", 0},
+		{"red", 1},
+		{"255,128,0", 1},
+		{"lab(50 20 -30)", 1},
+	} {
+		resolved, err := resolveExactColor("", c.colorName)
+		if err != nil {
+			t.Errorf("resolveExactColor(\"\", %q) failed: %s", c.colorName, err)
+			continue
+		}
+		report := newColorReport(c.colorName, resolved)
+		if report.Alpha == nil || *report.Alpha != c.alpha {
+			t.Errorf("newColorReport(%q).Alpha = %v, want %g", c.colorName, report.Alpha, c.alpha)
+		}
+	}
+	if report := newErrorReport("nope", errors.New("nope")); report.Alpha != nil {
+		t.Errorf("newErrorReport has alpha %g, want none", *report.Alpha)
+	}
+}
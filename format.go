@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+
+/* Notations accepted by -as. */
+var colorNotations = []string{"hex", "rgb", "hsl", "hsv", "lab", "oklab", "oklch", "cmyk"}
+
+
+/* Write a color in the given notation. */
+func formatColor(notation string, rgbColor color.RGBColor) (formatted string, err error) {
+	switch notation {
+	case "hex":
+		formatted = "#" + rgbColor.Hex()
+	case "rgb":
+		formatted = formatRGB(rgbColor)
+	case "hsl":
+		formatted = formatHSL(rgbColor)
+	case "hsv":
+		formatted = formatHSV(rgbColor)
+	case "lab":
+		formatted = formatLab(rgbColor)
+	case "oklab":
+		formatted = formatOKLab(rgbColor)
+	case "oklch":
+		formatted = formatOKLCH(rgbColor)
+	case "cmyk":
+		formatted = formatCMYK(rgbColor)
+	default:
+		err = fmt.Errorf("unknown notation %q", notation)
+	}
+	return
+}
+
+
+/* e.g. "rgb(70 130 180)" */
+func formatRGB(rgbColor color.RGBColor) string {
+	return fmt.Sprintf("rgb(%d %d %d)", rgbColor[0], rgbColor[1], rgbColor[2])
+}
+
+
+/* e.g. "hsl(207 44% 49%)" */
+func formatHSL(rgbColor color.RGBColor) string {
+	hue, saturation, lightness := rgbToHSL(rgbToFloats(rgbColor))
+	return fmt.Sprintf("hsl(%s %s%% %s%%)", formatNumber(hue, 1), formatNumber(saturation * 100, 1), formatNumber(lightness * 100, 1))
+}
+
+
+/* e.g. "hsv(207 61% 71%)" */
+func formatHSV(rgbColor color.RGBColor) string {
+	hue, saturation, value := rgbToHSV(rgbToFloats(rgbColor))
+	return fmt.Sprintf("hsv(%s %s%% %s%%)", formatNumber(hue, 1), formatNumber(saturation * 100, 1), formatNumber(value * 100, 1))
+}
+
+
+/* e.g. "lab(52.47 -4.08 -32.19)", relative to the -whitepoint */
+func formatLab(rgbColor color.RGBColor) string {
+	l, a, b := rgbToLabWhite(rgbColor, labWhitePoint)
+	return fmt.Sprintf("lab(%s %s %s)", formatNumber(l, 2), formatNumber(a, 2), formatNumber(b, 2))
+}
+
+
+/* e.g. "oklab(58.8% -0.04 -0.09)" */
+func formatOKLab(rgbColor color.RGBColor) string {
+	l, a, b := srgbToOKLab(rgbToFloats(rgbColor))
+	return fmt.Sprintf("oklab(%s%% %s %s)", formatNumber(l * 100, 1), formatNumber(a, 3), formatNumber(b, 3))
+}
+
+
+/* e.g. "oklch(62.8% 0.258 29.2)" */
+func formatOKLCH(rgbColor color.RGBColor) string {
+	lightness, chroma, hue := labToLCH(srgbToOKLab(rgbToFloats(rgbColor)))
+	if chroma < 0.0005 {
+		/* achromatic colors have no meaningful hue */
+		hue = 0
+	}
+	return fmt.Sprintf("oklch(%s%% %s %s)", formatNumber(lightness * 100, 1), formatNumber(chroma, 3), formatNumber(hue, 1))
+}
+
+
+/* Format a number with at most the given number of decimals, without trailing
+ * zeros. */
+func formatNumber(n float64, decimals int) string {
+	s := fmt.Sprintf("%.*f", decimals, n)
+	if decimals > 0 {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+
+/* e.g. "cmyk(61% 28% 0% 29%)" */
+func formatCMYK(rgbColor color.RGBColor) string {
+	c, m, y, k := rgbToCMYK(rgbToFloats(rgbColor))
+	return fmt.Sprintf("cmyk(%s%% %s%% %s%% %s%%)", formatNumber(c * 100, 0), formatNumber(m * 100, 0), formatNumber(y * 100, 0), formatNumber(k * 100, 0))
+}
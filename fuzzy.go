@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+
+/* Whether resolveColor uses the closest color name when there is exactly one,
+ * set by -fuzzy. */
+var fuzzyNames = false
+
+const maxSuggestions = 3
+
+var nameSeparators = strings.NewReplacer(" ", "", "-", "", "_", "")
+
+/* Input that could be a misspelled color name. */
+var nameLikePattern = regexp.MustCompile(`^[a-z][a-z0-9 _-]*$`)
+
+
+/* Edit distance between a and b, counting a swap of adjacent letters as one
+ * edit (optimal string alignment distance). */
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s) + 1)
+	for i := range d {
+		d[i] = make([]int, len(t) + 1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j] + 1, minInt(d[i][j-1] + 1, d[i-1][j-1] + cost))
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2] + 1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+
+/* The name tables a color type looks names up in. */
+func nameTables(colorType string) []map[string]color.RGBColor {
+	switch colorType {
+	case "":
+		return []map[string]color.RGBColor{colorAliases, loadedColors, webColors, x11Colors}
+	case "web":
+		return []map[string]color.RGBColor{webColors}
+	case "x11":
+		return []map[string]color.RGBColor{x11Colors}
+	case "alias":
+		return []map[string]color.RGBColor{colorAliases}
+	case "names":
+		return []map[string]color.RGBColor{loadedColors}
+	}
+	return nil
+}
+
+
+/* Known color names close to colorName, closest first, and whether the closest
+ * is unambiguously so. Separators are ignored, so "steel-blue" finds
+ * "steelblue" at distance 0. Longer names may be further off. */
+func nameSuggestions(colorType string, colorName string) (suggestions []string, unambiguous bool) {
+	s := strings.ToLower(strings.TrimSpace(colorName))
+	if ! nameLikePattern.MatchString(s) {
+		return
+	}
+	s = nameSeparators.Replace(s)
+	maxDistance := len(s) / 4 + 1
+	if maxDistance > 3 {
+		maxDistance = 3
+	}
+
+	distances := map[string]int{}
+	for _, table := range nameTables(colorType) {
+		for name := range table {
+			if strings.Contains(name, " ") {
+				continue
+			}
+			if d := editDistance(s, nameSeparators.Replace(name)); d <= maxDistance {
+				distances[name] = d
+			}
+		}
+	}
+	for name := range distances {
+		suggestions = append(suggestions, name)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if distances[a] != distances[b] {
+			return distances[a] < distances[b]
+		}
+		return lessNatural(a, b)
+	})
+
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	unambiguous = len(suggestions) == 1 || (len(suggestions) > 1 && distances[suggestions[0]] < distances[suggestions[1]])
+	return
+}
+
+
+/* "a", "a or b", "a, b or c", quoted. */
+func orList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = fmt.Sprintf("%q", item)
+	}
+	if len(quoted) < 2 {
+		return strings.Join(quoted, "")
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+
+func TestEditDistance(t *testing.T) {
+	for _, c := range []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"gray", "gray", 0},
+		{"gray", "grey", 1},
+		{"ab", "ba", 1},
+		{"steelbleu", "steelblue", 1},
+		{"kitten", "sitting", 3},
+		{"ca", "abc", 3},
+	} {
+		if d := editDistance(c.a, c.b); d != c.distance {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", c.a, c.b, d, c.distance)
+		}
+	}
+}
+
+
+func TestNameSuggestions(t *testing.T) {
+	for _, c := range []struct {
+		colorType   string
+		colorName   string
+		first       string
+		unambiguous bool
+	}{
+		{"", "steelblu", "steelblue", true},
+		{"", "Steel-Blue", "steelblue", true},
+		{"", "stelblue", "steelblue", true},
+		{"x11", "lightgoldenrodyelow", "lightgoldenrodyellow", true},
+		{"web", "gren", "green", false},
+		{"", "#12345", "", false},
+		{"", "notacolorname", "", false},
+	} {
+		suggestions, unambiguous := nameSuggestions(c.colorType, c.colorName)
+		first := ""
+		if len(suggestions) > 0 {
+			first = suggestions[0]
+		}
+		if first != c.first || unambiguous != c.unambiguous {
+			t.Errorf("nameSuggestions(%q, %q) = %q, %v, want %q first, %v", c.colorType, c.colorName, suggestions, unambiguous, c.first, c.unambiguous)
+		}
+		if len(suggestions) > maxSuggestions {
+			t.Errorf("nameSuggestions(%q, %q) = %q, more than %d", c.colorType, c.colorName, suggestions, maxSuggestions)
+		}
+	}
+}
+
+
+func TestOrList(t *testing.T) {
+	for _, c := range []struct {
+		items []string
+		list  string
+	}{
+		{nil, ""},
+		{[]string{"a"}, `"a"`},
+		{[]string{"a", "b"}, `"a" or "b"`},
+		{[]string{"a", "b", "c"}, `"a", "b" or "c"`},
+	} {
+		if list := orList(c.items); list != c.list {
+			t.Errorf("orList(%q) = %s, want %s", c.items, list, c.list)
+		}
+	}
+}
+
+
+/* With -fuzzy, a misspelled name resolves to the closest name, with a
+ * warning; without it, the closest names are suggested. */
+func TestResolveColorFuzzy(t *testing.T) {
+	defer func(fuzzy bool) { fuzzyNames = fuzzy }(fuzzyNames)
+
+	fuzzyNames = false
+	if _, err := resolveColor("", "steelblu"); err == nil || ! strings.Contains(err.Error(), `did you mean "steelblue"`) {
+		t.Errorf("resolveColor(\"steelblu\") without -fuzzy: got error %v, want a suggestion", err)
+	}
+
+	fuzzyNames = true
+	resolved, err := resolveColor("", "steelblu")
+	if err != nil {
+		t.Fatalf("resolveColor(\"steelblu\") with -fuzzy failed: %s", err)
+	}
+	if resolved.colorName != "steelblue" || resolved.rgbColor.Hex() != "4682b4" || resolved.warning == nil {
+		t.Errorf("resolveColor(\"steelblu\") with -fuzzy = %q #%s, warning %v", resolved.colorName, resolved.rgbColor.Hex(), resolved.warning)
+	}
+}
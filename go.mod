@@ -2,9 +2,8 @@ module colorview
 
 go 1.17
 
-require github.com/gookit/color v1.4.2
-
 require (
-	github.com/xo/terminfo v0.0.0-20210125001918-ca9a967f8778 // indirect
-	golang.org/x/sys v0.0.0-20210330210617-4fbd30eecc44 // indirect
+	github.com/gookit/color v1.4.2
+	github.com/xo/terminfo v0.0.0-20210125001918-ca9a967f8778
+	golang.org/x/sys v0.0.0-20210330210617-4fbd30eecc44
 )
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+
+/* Color literals in running text: hex colors, color functions and words that
+ * may be color names. Hex comes first so that "#bad" is not read as a word. */
+var colorLiteralPattern = regexp.MustCompile(
+	`#(?:[0-9A-Fa-f]{8}|[0-9A-Fa-f]{6}|[0-9A-Fa-f]{3,4})\b` +
+	`|\b(?i:rgba?|hsla?|hwb|hsv|hsb|lab|lch|oklab|oklch|color)\([^()]*\)` +
+	`|\b[A-Za-z][A-Za-z0-9]*\b`)
+
+
+/* Color types tried, in order, for each kind of literal matched by
+ * colorLiteralPattern. */
+var (
+	hexLiteralTypes      = []string{"hex", "css"}
+	functionLiteralTypes = []string{"css", "rgb", "hsl", "hsv", "lab", "lch", "oklab", "oklch"}
+	nameLiteralTypes     = []string{"web", "x11"}
+)
+
+
+/* The color of a literal found by colorLiteralPattern, if it is one. */
+func colorLiteral(literal string) (rgbColor color.RGBColor, ok bool) {
+	colorTypes := nameLiteralTypes
+	switch {
+	case strings.HasPrefix(literal, "#"):
+		colorTypes = hexLiteralTypes
+	case strings.HasSuffix(literal, ")"):
+		colorTypes = functionLiteralTypes
+	case strings.EqualFold(literal, "transparent"):
+		return
+	}
+	for _, colorType := range colorTypes {
+		rgbColor, _, err := transformColor(colorType, literal)
+		if err == nil || isOutOfGamut(err) {
+			return rgbColor, true
+		}
+	}
+	return
+}
+
+
+/* A color literal in a line, as a byte range. */
+type literalMatch struct {
+	start, end int
+	rgbColor   color.RGBColor
+}
+
+
+func findColorLiterals(line string) (matches []literalMatch) {
+	for _, loc := range colorLiteralPattern.FindAllStringIndex(line, -1) {
+		if rgbColor, ok := colorLiteral(line[loc[0]:loc[1]]); ok {
+			matches = append(matches, literalMatch{loc[0], loc[1], rgbColor})
+		}
+	}
+	return
+}
+
+
+/* Copy r to standard output line by line, painting each color literal on its
+ * own color. With onlyMatching, print just the literals with their line
+ * numbers and values instead. */
+func highlightColors(r io.Reader, onlyMatching bool) (status int) {
+	reader := bufio.NewReader(r)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			text := strings.TrimRight(line, "\r\n")
+			matches := findColorLiterals(text)
+			if onlyMatching {
+				for _, match := range matches {
+					literal := text[match.start:match.end]
+					fmt.Printf("%d: %s #%s\n", lineNumber, paint(literal, readableTextColor(match.rgbColor), match.rgbColor), match.rgbColor.Hex())
+				}
+			} else {
+				var sb strings.Builder
+				last := 0
+				for _, match := range matches {
+					sb.WriteString(text[last:match.start])
+					sb.WriteString(paint(text[match.start:match.end], readableTextColor(match.rgbColor), match.rgbColor))
+					last = match.end
+				}
+				sb.WriteString(line[last:])
+				fmt.Print(sb.String())
+			}
+		}
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return STATUS_INVALID_COLOR
+		}
+	}
+}
+
+
+/* Highlight each of the named files in turn, or standard input if there are
+ * none or the name is "-". Returns the status of the first that failed. */
+func highlightFiles(fileNames []string, onlyMatching bool) (status int) {
+	if len(fileNames) == 0 {
+		fileNames = []string{"-"}
+	}
+	for _, fileName := range fileNames {
+		var fileStatus int
+		if fileName == "-" {
+			fileStatus = highlightColors(os.Stdin, onlyMatching)
+		} else if f, err := os.Open(fileName); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			fileStatus = STATUS_INVALID_COLOR
+		} else {
+			fileStatus = highlightColors(f, onlyMatching)
+			f.Close()
+		}
+		if status == 0 {
+			status = fileStatus
+		}
+	}
+	return
+}
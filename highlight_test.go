@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+
+func TestFindColorLiterals(t *testing.T) {
+	for _, c := range []struct {
+		line     string
+		literals string
+	}{
+		{"color: #f80;", "#f80 #ff8800"},
+		{"rgb(255 128 0) and hsl(120, 100%, 50%)", "rgb(255 128 0) #ff8000, hsl(120, 100%, 50%) #00ff00"},
+		{"lch(50 30 120) lab(50 150 0) oklch(1 0 0)", "lch(50 30 120) #6d7d4a, lab(50 150 0) #ff007e, oklch(1 0 0) #ffffff"},
+		{"steelblue and x11gray", "steelblue #4682b4, x11gray #bebebe"},
+		{"no colors, transparent, rgb(1 2), #12345", ""},
+	} {
+		var literals []string
+		for _, match := range findColorLiterals(c.line) {
+			literals = append(literals, fmt.Sprintf("%s #%s", c.line[match.start:match.end], match.rgbColor.Hex()))
+		}
+		if got := strings.Join(literals, ", "); got != c.literals {
+			t.Errorf("findColorLiterals(%q) = %q, want %q", c.line, got, c.literals)
+		}
+	}
+}
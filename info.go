@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+
+/* Every representation of a color, in display order. */
+func colorInfo(rgbColor color.RGBColor) (rows [][2]string) {
+	for _, notation := range []string{"hex", "rgb", "hsl", "hsv", "lab", "oklch", "cmyk"} {
+		formatted, _ := formatColor(notation, rgbColor)
+		rows = append(rows, [2]string{notation, formatted})
+	}
+
+	c256, distance := rgbToXterm256(rgbColor)
+	rows = append(rows, [2]string{"xterm256", fmt.Sprintf("%d #%s (ΔE %.2f)", c256, xterm256Palette[c256].Hex(), distance)})
+
+	ansi, distance := rgbToANSI16(rgbColor)
+	rows = append(rows, [2]string{"ansi16", fmt.Sprintf("%d %s (ΔE %.2f)", ansi, ansi16Names[ansi], distance)})
+
+	nearest := nearestNamedColors(rgbColor, 1, "x11")[0]
+	rows = append(rows, [2]string{"x11", fmt.Sprintf("%s (ΔE %.2f)", strings.Join(nearest.names, ", "), nearest.distance)})
+	return
+}
+
+
+/* Print a swatch of the color, with every representation of it beside it. */
+func printInfo(rgbColor color.RGBColor, label string, swatch swatchOptions) {
+	rows := append([][2]string{{"", label}}, colorInfo(rgbColor)...)
+
+	width := 0
+	for _, row := range rows {
+		if len(row[0]) > width {
+			width = len(row[0])
+		}
+	}
+
+	swatch.height, swatch.label = len(rows), "none"
+	if swatch.style == "halfblocks" {
+		swatch.height *= 2
+	}
+	swatchLines := renderSwatch(rgbColor, "", swatch)
+	for i, row := range rows {
+		fmt.Printf("%s  %-*s  %s\n", swatchLines[i], width, row[0], row[1])
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gookit/color"
+)
+
+
+/* Orders accepted by list -sort. */
+var listSorts = []string{"name", "hue", "lightness", "luminance"}
+
+const listSwatchWidth = 4
+
+
+/* An X11 color name with its alternative spellings, e.g. "darkslategray" with
+ * "darkslate gray", "darkslate grey" and "darkslategrey". */
+type listEntry struct {
+	name     string
+	aliases  []string
+	rgbColor color.RGBColor
+}
+
+
+/* The spelling all aliases of an X11 color name share: no spaces, and gray
+ * rather than grey. */
+func aliasKey(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, " ", ""), "grey", "gray")
+}
+
+
+/* Every X11 color and loaded name, one entry per name, or one per color with
+ * its alias spellings folded in unless all is set. Loaded names replace X11
+ * names of the same spelling. */
+func x11ColorEntries(all bool) []listEntry {
+	names := map[string]color.RGBColor{}
+	for name, rgbColor := range x11Colors {
+		names[name] = rgbColor
+	}
+	for name, rgbColor := range loadedColors {
+		names[name] = rgbColor
+	}
+
+	var entries []listEntry
+	byKey := map[string]int{}
+	var members [][]string
+	for name, rgbColor := range names {
+		key := name
+		if ! all {
+			key = aliasKey(name)
+		}
+		i, ok := byKey[key]
+		if ! ok {
+			i = len(entries)
+			byKey[key] = i
+			entries = append(entries, listEntry{name: key, rgbColor: rgbColor})
+			members = append(members, nil)
+		}
+		members[i] = append(members[i], name)
+	}
+	for i := range entries {
+		sort.Strings(members[i])
+		entries[i].name = displayMember(entries[i].name, members[i])
+		for _, name := range members[i] {
+			if name != entries[i].name {
+				entries[i].aliases = append(entries[i].aliases, name)
+			}
+		}
+	}
+	return entries
+}
+
+
+/* The name an entry is listed under: the member spelled like key if there is
+ * one, or else the first without spaces, or else the first. The key itself is
+ * not always a name, e.g. "brandgray" for a loaded "brand grey". */
+func displayMember(key string, members []string) string {
+	for _, name := range members {
+		if name == key {
+			return name
+		}
+	}
+	for _, name := range members {
+		if ! strings.Contains(name, " ") {
+			return name
+		}
+	}
+	return members[0]
+}
+
+
+/* Whether the entry's name or any of its aliases matches. */
+func (e listEntry) matches(match func(string) bool) bool {
+	if match(e.name) {
+		return true
+	}
+	for _, alias := range e.aliases {
+		if match(alias) {
+			return true
+		}
+	}
+	return false
+}
+
+
+/* Compare names with trailing numbers by value, so that gray9 comes before
+ * gray10. */
+func lessNatural(a, b string) bool {
+	splitNumber := func(s string) (string, int) {
+		i := len(s)
+		for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+			i--
+		}
+		n, err := strconv.Atoi(s[i:])
+		if err != nil {
+			return s, -1
+		}
+		return s[:i], n
+	}
+	aName, aNumber := splitNumber(a)
+	bName, bNumber := splitNumber(b)
+	if aName != bName {
+		return aName < bName
+	}
+	return aNumber < bNumber
+}
+
+
+/* Sort entries by the given listSorts order, then by name. Grays come first
+ * when sorting by hue, since they have none. */
+func sortEntries(entries []listEntry, order string) {
+	keys := make([][2]float64, len(entries))
+	for i, entry := range entries {
+		r, g, b := rgbToFloats(entry.rgbColor)
+		switch order {
+		case "hue":
+			hue, saturation, _ := rgbToHSL(r, g, b)
+			if saturation == 0 {
+				hue = -1
+			}
+			l, _, _ := rgbToLab(entry.rgbColor)
+			keys[i] = [2]float64{hue, l}
+		case "lightness":
+			l, _, _ := rgbToLab(entry.rgbColor)
+			keys[i] = [2]float64{l, 0}
+		case "luminance":
+			keys[i] = [2]float64{relativeLuminance(entry.rgbColor), 0}
+		}
+	}
+	sort.Sort(entrySorter{entries, keys})
+}
+
+
+type entrySorter struct {
+	entries []listEntry
+	keys    [][2]float64
+}
+
+func (s entrySorter) Len() int {
+	return len(s.entries)
+}
+
+func (s entrySorter) Less(i, j int) bool {
+	if s.keys[i] != s.keys[j] {
+		if s.keys[i][0] != s.keys[j][0] {
+			return s.keys[i][0] < s.keys[j][0]
+		}
+		return s.keys[i][1] < s.keys[j][1]
+	}
+	return lessNatural(s.entries[i].name, s.entries[j].name)
+}
+
+func (s entrySorter) Swap(i, j int) {
+	s.entries[i], s.entries[j] = s.entries[j], s.entries[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+
+/* The list subcommand: print the X11 colors matching an optional pattern, each
+ * with a swatch, hex and RGB value, in as many columns as fit. */
+func listColors(args []string) (status int) {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: colorview list [flags] [pattern]")
+		flags.PrintDefaults()
+	}
+	sortFlag := flags.String("sort", "name", "Sort order. Must be one of: " + quoteList(listSorts) + ".")
+	regexFlag := flags.Bool("regex", false, "Match the pattern as a regular expression instead of a substring.")
+	allFlag := flags.Bool("all", false, "List every spelling of a name (gray/grey, with spaces) separately.")
+	columnsFlag := flags.Int("columns", 0, "Number of columns; 0 fits as many as the terminal is wide.")
+
+	/* flags may come after the pattern too */
+	var patterns []string
+	for flags.Parse(args); flags.NArg() > 0; flags.Parse(args) {
+		patterns = append(patterns, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+
+	order := cleanString(*sortFlag)
+	if ! containsString(listSorts, order) {
+		dieImmediate(STATUS_UNKNOWN_COLORTYPE, "Unknown sort order:", *sortFlag)
+	}
+	if len(patterns) > 1 {
+		dieImmediate(STATUS_INVALID_COLOR, "Expected at most one pattern")
+	}
+	pattern := strings.Join(patterns, "")
+
+	match := func(string) bool { return true }
+	if *regexFlag {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			dieImmediate(STATUS_INVALID_COLOR, "Invalid pattern:", err.Error())
+		}
+		match = re.MatchString
+	} else if len(pattern) > 0 {
+		pattern = strings.ToLower(pattern)
+		match = func(name string) bool { return strings.Contains(name, pattern) }
+	}
+
+	var entries []listEntry
+	for _, entry := range x11ColorEntries(*allFlag) {
+		if entry.matches(match) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(os.Stderr, "No colors match", pattern)
+		return STATUS_INVALID_COLOR
+	}
+	sortEntries(entries, order)
+	printGrid(entries, *columnsFlag)
+	return 0
+}
+
+
+/* Print entries in columns, top to bottom then left to right like ls. */
+func printGrid(entries []listEntry, columns int) {
+	nameWidth := 0
+	for _, entry := range entries {
+		if n := utf8.RuneCountInString(entry.name); n > nameWidth {
+			nameWidth = n
+		}
+	}
+	cell := func(entry listEntry) string {
+		rgb := fmt.Sprintf("%d,%d,%d", entry.rgbColor[0], entry.rgbColor[1], entry.rgbColor[2])
+		return fmt.Sprintf("%s %s #%s %-11s", paint(strings.Repeat(" ", listSwatchWidth), entry.rgbColor), padRight(entry.name, nameWidth), entry.rgbColor.Hex(), rgb)
+	}
+	const gutter = 2
+	cellWidth := listSwatchWidth + 1 + nameWidth + 1 + 7 + 1 + 11
+
+	if columns <= 0 {
+		width := terminalWidth()
+		if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+			width = n
+		}
+		if width <= 0 {
+			width = 80
+		}
+		columns = (width + gutter) / (cellWidth + gutter)
+		if columns < 1 {
+			columns = 1
+		}
+	}
+	rows := (len(entries) + columns - 1) / columns
+
+	for row := 0; row < rows; row++ {
+		var cells []string
+		for column := 0; column < columns; column++ {
+			if i := column * rows + row; i < len(entries) {
+				cells = append(cells, cell(entries[i]))
+			}
+		}
+		fmt.Println(strings.TrimRight(strings.Join(cells, strings.Repeat(" ", gutter)), " "))
+	}
+}
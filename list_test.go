@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+
+func TestAliasKey(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		key  string
+	}{
+		{"darkslategray", "darkslategray"},
+		{"darkslate grey", "darkslategray"},
+		{"dark slate grey", "darkslategray"},
+		{"grey50", "gray50"},
+		{"steelblue", "steelblue"},
+	} {
+		if key := aliasKey(c.name); key != c.key {
+			t.Errorf("aliasKey(%q) = %q, want %q", c.name, key, c.key)
+		}
+	}
+}
+
+
+func TestLessNatural(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		less bool
+	}{
+		{"gray9", "gray10", true},
+		{"gray10", "gray9", false},
+		{"gray", "gray0", true},
+		{"gray100", "green", true},
+		{"blue4", "blue4", false},
+		{"azure", "beige", true},
+	} {
+		if less := lessNatural(c.a, c.b); less != c.less {
+			t.Errorf("lessNatural(%q, %q) = %v, want %v", c.a, c.b, less, c.less)
+		}
+	}
+}
+
+
+/* Spellings of one X11 color are folded into one entry, unless all is set. */
+func TestX11ColorEntries(t *testing.T) {
+	find := func(entries []listEntry, name string) (listEntry, bool) {
+		for _, entry := range entries {
+			if entry.name == name {
+				return entry, true
+			}
+		}
+		return listEntry{}, false
+	}
+
+	entries := x11ColorEntries(false)
+	entry, ok := find(entries, "darkslategray")
+	if ! ok {
+		t.Fatalf("no darkslategray entry")
+	}
+	if aliases := strings.Join(entry.aliases, ", "); aliases != "darkslate gray, darkslate grey, darkslategrey" {
+		t.Errorf("darkslategray aliases = %q", aliases)
+	}
+	if _, ok := find(entries, "darkslategrey"); ok {
+		t.Errorf("darkslategrey listed separately without -all")
+	}
+	if ! entry.matches(func(s string) bool { return strings.HasSuffix(s, "grey") }) {
+		t.Errorf("darkslategray does not match its alias darkslategrey")
+	}
+
+	if _, ok := find(x11ColorEntries(true), "darkslategrey"); ! ok {
+		t.Errorf("darkslategrey not listed with -all")
+	}
+}
+
+
+func TestDisplayMember(t *testing.T) {
+	for _, c := range []struct {
+		key     string
+		members []string
+		name    string
+	}{
+		{"darkslategray", []string{"darkslate gray", "darkslategray", "darkslategrey"}, "darkslategray"},
+		{"brandgray", []string{"brand grey", "brandgrey"}, "brandgrey"},
+		{"brandgray", []string{"brand grey"}, "brand grey"},
+	} {
+		if name := displayMember(c.key, c.members); name != c.name {
+			t.Errorf("displayMember(%q, %q) = %q, want %q", c.key, c.members, name, c.name)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+
+/* Values of a flag that may be given more than once. */
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+
+/* $XDG_CONFIG_HOME/colorview, or ~/.config/colorview. */
+func configDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); len(dir) > 0 {
+		return filepath.Join(dir, "colorview")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "colorview")
+}
+
+
+/* The names file loaded when no -names-file is given, if it exists. */
+func defaultNamesFile() string {
+	if dir := configDir(); len(dir) > 0 {
+		return filepath.Join(dir, "names")
+	}
+	return ""
+}
+
+
+/* Color names loaded from -names-file or the default names file. They are
+ * tried before the built-in names, so they can override them. */
+var loadedColors = map[string]color.RGBColor{}
+
+
+/* Where a loaded name was first defined. */
+type nameDefinition struct {
+	fileName string
+	line     int
+}
+
+
+/* Load the named files into loadedColors, in order, so that later names
+ * override earlier ones. Returns the problems in the files, each with its file
+ * and line number, including names defined again in a later file; a file that
+ * cannot be read stops loading. */
+func loadNamesFiles(fileNames []string) (problems []error, err error) {
+	definedOn := map[string]nameDefinition{}
+	for _, fileName := range fileNames {
+		f, err := os.Open(fileName)
+		if err != nil {
+			return problems, err
+		}
+		problems = append(problems, loadNames(f, fileName, loadedColors, definedOn)...)
+		f.Close()
+	}
+	return problems, nil
+}
+
+
+/* Read color names into table from r, in X11 rgb.txt format ("255 250 250
+ * snow") or as "name = value", where value is anything colorview accepts,
+ * including names defined earlier. Blank lines and lines starting with ! or #
+ * are ignored. Returns the lines that could not be read and the names already
+ * in definedOn, to which the names in r are added. */
+func loadNames(r io.Reader, fileName string, table map[string]color.RGBColor, definedOn map[string]nameDefinition) (problems []error) {
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "!") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		problem := func(format string, a ...interface{}) {
+			problems = append(problems, fmt.Errorf("%s:%d: %s", fileName, lineNumber, fmt.Sprintf(format, a...)))
+		}
+
+		var name string
+		var rgbColor color.RGBColor
+		var err error
+		if i := strings.IndexByte(line, '='); i >= 0 {
+			name, rgbColor, err = parseNameAssignment(line[:i], line[i+1:])
+		} else {
+			name, rgbColor, err = parseRGBTxtLine(line)
+		}
+		if err != nil {
+			problem("%s", err)
+			continue
+		}
+
+		key := cleanString(name)
+		if first, ok := definedOn[key]; ! ok {
+			definedOn[key] = nameDefinition{fileName, lineNumber}
+		} else if first.fileName == fileName {
+			problem("duplicate name %q, first defined on line %d", name, first.line)
+		} else {
+			problem("duplicate name %q, first defined in %s:%d", name, first.fileName, first.line)
+		}
+		table[key] = rgbColor
+	}
+	if err := scanner.Err(); err != nil {
+		problems = append(problems, fmt.Errorf("%s: %s", fileName, err))
+	}
+	return
+}
+
+
+/* A "name = value" line, split at the first =. */
+func parseNameAssignment(name string, value string) (string, color.RGBColor, error) {
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if len(name) == 0 {
+		return "", color.RGBColor{}, fmt.Errorf("missing name before =")
+	}
+	resolved, err := resolveExactColor("", value)
+	if err != nil {
+		return "", color.RGBColor{}, fmt.Errorf("invalid value for %q: %s", name, err)
+	}
+	return name, resolved.rgbColor, nil
+}
+
+
+/* An rgb.txt line: red, green and blue from 0 to 255, then the name, which may
+ * contain spaces. */
+func parseRGBTxtLine(line string) (string, color.RGBColor, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 {
+		return "", color.RGBColor{}, fmt.Errorf("expected \"red green blue name\" or \"name = value\", got %q", line)
+	}
+	var values [3]uint8
+	for i := range values {
+		n, err := strconv.ParseUint(fields[i], 10, 8)
+		if err != nil {
+			return "", color.RGBColor{}, fmt.Errorf("component %q is not a number from 0 to 255", fields[i])
+		}
+		values[i] = uint8(n)
+	}
+	return strings.Join(fields[3:], " "), color.RGB(values[0], values[1], values[2], true), nil
+}
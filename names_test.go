@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gookit/color"
+)
+
+
+/* Restore table to its current contents when the test is done. */
+func restoreTable(t *testing.T, table map[string]color.RGBColor) {
+	saved := map[string]color.RGBColor{}
+	for name, rgbColor := range table {
+		saved[name] = rgbColor
+	}
+	t.Cleanup(func() {
+		for name := range table {
+			delete(table, name)
+		}
+		for name, rgbColor := range saved {
+			table[name] = rgbColor
+		}
+	})
+}
+
+
+func TestParseRGBTxtLine(t *testing.T) {
+	for _, c := range []struct {
+		line string
+		name string
+		hex  string
+	}{
+		{"255 250 250 snow", "snow", "fffafa"},
+		{"  0   0 128\t\tnavy blue", "navy blue", "000080"},
+		{"0 0 0 black", "black", "000000"},
+		{"1 2 3", "", ""},
+		{"256 0 0 too red", "", ""},
+		{"-1 0 0 negative", "", ""},
+		{"a b c name", "", ""},
+	} {
+		name, rgbColor, err := parseRGBTxtLine(c.line)
+		switch {
+		case len(c.name) == 0 && err == nil:
+			t.Errorf("parseRGBTxtLine(%q) = %q #%s, want an error", c.line, name, rgbColor.Hex())
+		case len(c.name) > 0 && err != nil:
+			t.Errorf("parseRGBTxtLine(%q) failed: %s", c.line, err)
+		case len(c.name) > 0 && (name != c.name || rgbColor.Hex() != c.hex):
+			t.Errorf("parseRGBTxtLine(%q) = %q #%s, want %q #%s", c.line, name, rgbColor.Hex(), c.name, c.hex)
+		}
+	}
+}
+
+
+func TestLoadNames(t *testing.T) {
+	table := loadedColors
+	restoreTable(t, table)
+
+	problems := loadNames(strings.NewReader(`! rgb.txt comment
+# comment
+
+255 250 250 test snow
+  0   0 128 test navy
+test brand = #0055aa
+test accent = test brand
+bad line
+300 0 0 too red
+= #000000
+test snow = #000000
+test bad = nosuchcolor
+`), "names.txt", table, map[string]nameDefinition{})
+
+	for name, hex := range map[string]string{
+		"testsnow":   "000000",
+		"testnavy":   "000080",
+		"testbrand":  "0055aa",
+		"testaccent": "0055aa",
+	} {
+		if rgbColor, ok := table[name]; ! ok {
+			t.Errorf("%s was not loaded", name)
+		} else if rgbColor.Hex() != hex {
+			t.Errorf("%s = #%s, want #%s", name, rgbColor.Hex(), hex)
+		}
+	}
+	for _, name := range []string{"toored", "testbad"} {
+		if _, ok := table[name]; ok {
+			t.Errorf("%s was loaded from a bad line", name)
+		}
+	}
+
+	want := []string{
+		"names.txt:8: ",
+		"names.txt:9: ",
+		"names.txt:10: ",
+		`names.txt:11: duplicate name "test snow", first defined on line 4`,
+		"names.txt:12: ",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("got problems %q, want %d", problems, len(want))
+	}
+	for i, problem := range problems {
+		if ! strings.HasPrefix(problem.Error(), want[i]) {
+			t.Errorf("problem %d = %q, want it to start with %q", i, problem, want[i])
+		}
+	}
+}
+
+
+/* A name defined again in a later file overrides it, and is reported with
+ * both places. */
+func TestLoadNamesFiles(t *testing.T) {
+	restoreTable(t, loadedColors)
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first"), filepath.Join(dir, "second")
+	if err := os.WriteFile(first, []byte("test brand = #0055aa\ntest other = #000000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte("\ntest brand = #aa5500\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := loadNamesFiles([]string{first, second})
+	if err != nil {
+		t.Fatalf("loadNamesFiles failed: %s", err)
+	}
+	want := fmt.Sprintf(`%s:2: duplicate name "test brand", first defined in %s:1`, second, first)
+	if len(problems) != 1 || problems[0].Error() != want {
+		t.Errorf("got problems %q, want %q", problems, want)
+	}
+	if hex := loadedColors["testbrand"].Hex(); hex != "aa5500" {
+		t.Errorf("testbrand = #%s, want #aa5500 from the later file", hex)
+	}
+
+	if _, err := loadNamesFiles([]string{filepath.Join(dir, "missing")}); err == nil {
+		t.Errorf("loadNamesFiles of a missing file did not fail")
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+
+/* Named colors that share one RGB value, e.g. "aqua" and "cyan", with the
+ * sources each name has that value in. */
+type namedColor struct {
+	names    []string
+	sources  map[string][]string
+	rgbColor color.RGBColor
+}
+
+
+/* The names with their sources, e.g. "web:gray, x11:webgray". A name with the
+ * same value in several sources is listed once, as "web/x11:aqua". */
+func (c namedColor) label() string {
+	labels := make([]string, len(c.names))
+	for i, name := range c.names {
+		labels[i] = strings.Join(c.sources[name], "/") + ":" + name
+	}
+	return strings.Join(labels, ", ")
+}
+
+
+/* A namedColor and its CIEDE2000 distance from some other color. */
+type colorMatch struct {
+	namedColor
+	distance float64
+}
+
+
+/* All web, X11 and loaded color names, grouped by value, or only those from
+ * the given sources ("web", "x11", "names"). A name is only grouped with the
+ * value it has in each source, so X11 "gray" is not grouped with web "gray".
+ * The X11 spellings with spaces ("dark slate gray") are left out, since the
+ * spaceless spelling is always there too. */
+func namedColorGroups(sources ...string) []namedColor {
+	var groups []namedColor
+	byValue := map[color.RGBColor]int{}
+
+	add := func(name string, source string, rgbColor color.RGBColor) {
+		i, ok := byValue[rgbColor]
+		if ! ok {
+			i = len(groups)
+			byValue[rgbColor] = i
+			groups = append(groups, namedColor{sources: map[string][]string{}, rgbColor: rgbColor})
+		}
+		group := &groups[i]
+		if ! containsString(group.names, name) {
+			group.names = append(group.names, name)
+		}
+		if ! containsString(group.sources[name], source) {
+			group.sources[name] = append(group.sources[name], source)
+		}
+	}
+
+	for _, source := range []struct {
+		name   string
+		colors map[string]color.RGBColor
+	}{
+		{"web", webColors},
+		{"x11", x11Colors},
+		{"names", loadedColors},
+	} {
+		if len(sources) > 0 && ! containsString(sources, source.name) {
+			continue
+		}
+		for name, rgbColor := range source.colors {
+			if strings.Contains(name, " ") || name == "transparent" {
+				continue
+			}
+			add(name, source.name, rgbColor)
+		}
+	}
+
+	for i := range groups {
+		sort.Strings(groups[i].names)
+	}
+	return groups
+}
+
+
+/* The n named colors closest to rgbColor, closest first, optionally only from
+ * the given sources. */
+func nearestNamedColors(rgbColor color.RGBColor, n int, sources ...string) []colorMatch {
+	groups := namedColorGroups(sources...)
+	matches := make([]colorMatch, len(groups))
+	for i, group := range groups {
+		matches[i] = colorMatch{group, deltaE2000(rgbColor, group.rgbColor)}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].names[0] < matches[j].names[0]
+	})
+	if n < len(matches) {
+		matches = matches[:n]
+	}
+	return matches
+}
+
+
+/* Print the n named colors closest to rgbColor, each on its own background. */
+func printNearest(rgbColor color.RGBColor, n int) {
+	matches := nearestNamedColors(rgbColor, n)
+
+	width := 0
+	for _, match := range matches {
+		if w := len(match.label()); w > width {
+			width = w
+		}
+	}
+
+	for _, match := range matches {
+		label := fmt.Sprintf(" %-*s ", width, match.label())
+		fmt.Printf("%s  #%s  ΔE %5.2f\n", paint(label, match.rgbColor), match.rgbColor.Hex(), match.distance)
+	}
+}
@@ -0,0 +1,572 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+
+/* Split a color like "hsl(210deg 50% 40%)", "210,50%,40%" or "210 50 40" into
+ * its components. If the color is written in functional notation, the function
+ * name must be one of funcNames. */
+func splitColorComponents(colorName string, funcNames ...string) (components []string, err error) {
+	s := strings.ToLower(strings.TrimSpace(colorName))
+
+	if open := strings.IndexByte(s, '('); open >= 0 {
+		funcName := strings.TrimSpace(s[:open])
+		if ! containsString(funcNames, funcName) {
+			return nil, fmt.Errorf("unexpected color function %q", funcName)
+		}
+		if ! strings.HasSuffix(s, ")") {
+			return nil, fmt.Errorf("missing closing parenthesis in %q", colorName)
+		}
+		s = s[open+1 : len(s)-1]
+	}
+
+	components = strings.Fields(strings.ReplaceAll(s, ",", " "))
+	if len(components) == 0 {
+		return nil, fmt.Errorf("no color components in %q", colorName)
+	}
+	return components, nil
+}
+
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
+
+
+/* Parse a finite number. strconv.ParseFloat also accepts "NaN" and "Inf",
+ * which are not color components. */
+func parseNumber(s string) (float64, error) {
+	n, err := strconv.ParseFloat(s, 64)
+	if err == nil && (math.IsNaN(n) || math.IsInf(n, 0)) {
+		err = fmt.Errorf("%q is not a finite number", s)
+	}
+	return n, err
+}
+
+
+/* Degrees per unit of the CSS angle units. "grad" is listed before "rad" so
+ * that it is matched first. */
+var angleUnits = []struct {
+	suffix  string
+	degrees float64
+}{
+	{"deg", 1},
+	{"grad", 360.0 / 400},
+	{"rad", 180 / math.Pi},
+	{"turn", 360},
+}
+
+
+/* Parse an angle in degrees, e.g. "210", "210deg", "0.5turn", "3.7rad" or
+ * "233grad". */
+func parseAngle(s string) (degrees float64, err error) {
+	scale := 1.0
+	number := s
+	for _, unit := range angleUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			number, scale = strings.TrimSuffix(s, unit.suffix), unit.degrees
+			break
+		}
+	}
+	degrees, err = parseNumber(number)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hue %q", s)
+	}
+	return degrees * scale, nil
+}
+
+
+/* Parse a hue angle (see parseAngle), normalized to [0, 360). */
+func parseHue(s string) (hue float64, err error) {
+	hue, err = parseAngle(s)
+	if err != nil {
+		return
+	}
+	hue = mod(hue, 360)
+	return
+}
+
+
+/* Parse a percentage such as "50%" or "50" into a fraction in [0, 1]. */
+func parsePercent(s string, componentName string) (fraction float64, err error) {
+	percent, err := parseNumber(strings.TrimSuffix(s, "%"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", componentName, s)
+	}
+	if percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("%s %q out of range 0-100%%", componentName, s)
+	}
+	return percent / 100, nil
+}
+
+
+/* Parse a fraction such as "0.5", which must be in [0, 1]. */
+func parseFraction(s string, componentName string) (fraction float64, err error) {
+	fraction, err = parseNumber(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", componentName, s)
+	}
+	if fraction < 0 || fraction > 1 {
+		return 0, fmt.Errorf("%s %q out of range 0-1", componentName, s)
+	}
+	return fraction, nil
+}
+
+
+/* Parse an HSL color: "210,50%,40%", "210 50 40" or "hsl(210deg 50% 40%)".
+ * Saturation and lightness are always percentages, with or without the "%". */
+func parseHSL(colorName string) (rgbColor color.RGBColor, err error) {
+	components, err := splitColorComponents(colorName, "hsl")
+	if err != nil {
+		return
+	}
+	if len(components) != 3 {
+		err = fmt.Errorf("expected 3 HSL components, got %d", len(components))
+		return
+	}
+
+	hue, err := parseHue(components[0])
+	if err != nil {
+		return
+	}
+	saturation, err := parsePercent(components[1], "saturation")
+	if err != nil {
+		return
+	}
+	lightness, err := parsePercent(components[2], "lightness")
+	if err != nil {
+		return
+	}
+
+	rgbColor = rgbFromFloats(hslToRGB(hue, saturation, lightness))
+	return
+}
+
+
+/* Parse an HSV (a.k.a. HSB) color: "210,50%,40%", "210 0.5 0.4" or
+ * "hsv(210 50 40)". Saturation and value are read as 0-1 fractions if neither
+ * is written as a percentage or is greater than 1, otherwise as 0-100
+ * percentages. */
+func parseHSV(colorName string) (rgbColor color.RGBColor, err error) {
+	defer func() { err = functionalNotationError(colorName, err, "hsv", "hsb") }()
+	components, err := splitColorComponents(colorName, "hsv", "hsb")
+	if err != nil {
+		return
+	}
+	if len(components) != 3 {
+		err = fmt.Errorf("expected 3 HSV components, got %d", len(components))
+		return
+	}
+
+	hue, err := parseAngle(components[0])
+	if err != nil {
+		return
+	}
+	if hue < 0 || hue > 360 {
+		err = fmt.Errorf("hue %q out of range 0-360", components[0])
+		return
+	}
+
+	isPercent := false
+	for _, component := range components[1:] {
+		n, _ := strconv.ParseFloat(strings.TrimSuffix(component, "%"), 64)
+		if strings.HasSuffix(component, "%") || n > 1 {
+			isPercent = true
+		}
+	}
+
+	var saturation, value float64
+	if isPercent {
+		if saturation, err = parsePercent(components[1], "saturation"); err != nil {
+			return
+		}
+		if value, err = parsePercent(components[2], "value"); err != nil {
+			return
+		}
+	} else {
+		if saturation, err = parseFraction(components[1], "saturation"); err != nil {
+			return
+		}
+		if value, err = parseFraction(components[2], "value"); err != nil {
+			return
+		}
+	}
+
+	rgbColor = rgbFromFloats(hsvToRGB(mod(hue, 360), saturation, value))
+	return
+}
+
+
+/* Returned along with the nearest displayable (clamped or gamut mapped) color
+ * when a color is valid but cannot be displayed in sRGB. */
+type outOfGamutError struct {
+	colorName string
+	displayed color.RGBColor
+}
+
+func (e *outOfGamutError) Error() string {
+	return fmt.Sprintf("%s is outside the sRGB gamut, displayed as #%s", e.colorName, e.displayed.Hex())
+}
+
+
+/* A color that is unmistakably of one type but invalid, e.g. "300,0,0" is an
+ * RGB color with red out of range, not an HSL color. */
+type invalidColorError struct {
+	message string
+}
+
+func (e *invalidColorError) Error() string {
+	return e.message
+}
+
+
+/* Whether auto-detection should stop at err instead of trying other types. */
+func isDefinitelyInvalid(err error) bool {
+	var invalidErr *invalidColorError
+	return errors.As(err, &invalidErr) || isCSSParseError(err)
+}
+
+
+/* err as an *invalidColorError if colorName is written in functional notation
+ * with one of funcNames, e.g. "hsv(400 50% 50%)", which can be no other type.
+ * Out of gamut warnings are returned as they are. */
+func functionalNotationError(colorName string, err error, funcNames ...string) error {
+	if err == nil || isOutOfGamut(err) || isDefinitelyInvalid(err) {
+		return err
+	}
+	s := strings.ToLower(strings.TrimSpace(colorName))
+	if open := strings.IndexByte(s, '('); open >= 0 && containsString(funcNames, strings.TrimSpace(s[:open])) {
+		return &invalidColorError{err.Error()}
+	}
+	return err
+}
+
+
+func isOutOfGamut(err error) bool {
+	var gamutErr *outOfGamutError
+	return errors.As(err, &gamutErr)
+}
+
+
+/* White point used to interpret L*a*b* colors, set by the -whitepoint flag. */
+var labWhitePoint = whiteD65
+
+
+/* Parse a CIE L*a*b* color: "54,81,70", "54 81 70" or "lab(54% 81 70)".
+ * Colors outside the sRGB gamut are clamped and returned with an
+ * *outOfGamutError. */
+func parseLab(colorName string) (rgbColor color.RGBColor, err error) {
+	defer func() { err = functionalNotationError(colorName, err, "lab") }()
+	components, err := splitColorComponents(colorName, "lab")
+	if err != nil {
+		return
+	}
+	if len(components) != 3 {
+		err = fmt.Errorf("expected 3 L*a*b* components, got %d", len(components))
+		return
+	}
+
+	lightness, err := parsePercent(components[0], "lightness")
+	if err != nil {
+		return
+	}
+	var ab [2]float64
+	for i, name := range [2]string{"a", "b"} {
+		ab[i], err = parseNumber(components[i+1])
+		if err != nil {
+			err = fmt.Errorf("invalid %s %q", name, components[i+1])
+			return
+		}
+	}
+
+	r, g, b := labToSRGB(lightness * 100, ab[0], ab[1], labWhitePoint)
+	rgbColor = rgbFromFloats(r, g, b)
+	if ! inSRGBGamut(r, g, b) {
+		err = &outOfGamutError{colorName, rgbColor}
+	}
+	return
+}
+
+
+/* Parse a CIE LCH color, the polar form of L*a*b*: "54,107,40",
+ * "54 107 40deg" or "lch(54% 107 40)". A chroma percentage is of 150. Colors
+ * outside the sRGB gamut are clamped and returned with an *outOfGamutError. */
+func parseLCH(colorName string) (rgbColor color.RGBColor, err error) {
+	defer func() { err = functionalNotationError(colorName, err, "lch") }()
+	components, err := splitColorComponents(colorName, "lch")
+	if err != nil {
+		return
+	}
+	if len(components) != 3 {
+		err = fmt.Errorf("expected 3 LCH components, got %d", len(components))
+		return
+	}
+
+	lightness, err := parsePercent(components[0], "lightness")
+	if err != nil {
+		return
+	}
+	chroma, err := parseScaledNumber(components[1], "chroma", 150)
+	if err != nil {
+		return
+	}
+	if chroma < 0 {
+		err = fmt.Errorf("chroma %q must not be negative", components[1])
+		return
+	}
+	hue, err := parseHue(components[2])
+	if err != nil {
+		return
+	}
+
+	l, a, bb := lchToLab(lightness * 100, chroma, hue)
+	r, g, b := labToSRGB(l, a, bb, labWhitePoint)
+	rgbColor = rgbFromFloats(r, g, b)
+	if ! inSRGBGamut(r, g, b) {
+		err = &outOfGamutError{colorName, rgbColor}
+	}
+	return
+}
+
+
+/* Parse a number in [0, 1] or a percentage, e.g. OKLab lightness "0.63" or
+ * "63%". */
+func parseFractionOrPercent(s string, componentName string) (fraction float64, err error) {
+	if strings.HasSuffix(s, "%") {
+		return parsePercent(s, componentName)
+	}
+	return parseFraction(s, componentName)
+}
+
+
+/* Parse a number, or a percentage of percentScale, e.g. OKLab a/b "0.1" or
+ * "25%" where 100% is 0.4. */
+func parseScaledNumber(s string, componentName string, percentScale float64) (n float64, err error) {
+	n, err = parseNumber(strings.TrimSuffix(s, "%"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", componentName, s)
+	}
+	if strings.HasSuffix(s, "%") {
+		n = n / 100 * percentScale
+	}
+	return n, nil
+}
+
+
+/* Parse an OKLab color: "0.63,0.22,0.13", "0.63 0.22 0.13" or
+ * "oklab(63% 0.22 0.13)". Colors outside the sRGB gamut are gamut mapped and
+ * returned with an *outOfGamutError. */
+func parseOKLab(colorName string) (rgbColor color.RGBColor, err error) {
+	defer func() { err = functionalNotationError(colorName, err, "oklab") }()
+	components, err := splitColorComponents(colorName, "oklab")
+	if err != nil {
+		return
+	}
+	if len(components) != 3 {
+		err = fmt.Errorf("expected 3 OKLab components, got %d", len(components))
+		return
+	}
+
+	lightness, err := parseFractionOrPercent(components[0], "lightness")
+	if err != nil {
+		return
+	}
+	a, err := parseScaledNumber(components[1], "a", 0.4)
+	if err != nil {
+		return
+	}
+	b, err := parseScaledNumber(components[2], "b", 0.4)
+	if err != nil {
+		return
+	}
+
+	lightness, chroma, hue := labToLCH(lightness, a, b)
+	return gamutMappedOKLCH(colorName, lightness, chroma, hue)
+}
+
+
+/* Parse an OKLCH color: "0.63,0.26,29", "0.63 0.26 29deg" or
+ * "oklch(63% 0.26 29)". Colors outside the sRGB gamut are gamut mapped and
+ * returned with an *outOfGamutError. */
+func parseOKLCH(colorName string) (rgbColor color.RGBColor, err error) {
+	defer func() { err = functionalNotationError(colorName, err, "oklch") }()
+	components, err := splitColorComponents(colorName, "oklch")
+	if err != nil {
+		return
+	}
+	if len(components) != 3 {
+		err = fmt.Errorf("expected 3 OKLCH components, got %d", len(components))
+		return
+	}
+
+	lightness, err := parseFractionOrPercent(components[0], "lightness")
+	if err != nil {
+		return
+	}
+	chroma, err := parseScaledNumber(components[1], "chroma", 0.4)
+	if err != nil {
+		return
+	}
+	if chroma < 0 {
+		err = fmt.Errorf("chroma %q must not be negative", components[1])
+		return
+	}
+	hue, err := parseHue(components[2])
+	if err != nil {
+		return
+	}
+
+	return gamutMappedOKLCH(colorName, lightness, chroma, hue)
+}
+
+
+func gamutMappedOKLCH(colorName string, lightness, chroma, hue float64) (rgbColor color.RGBColor, err error) {
+	r, g, b := gamutMapOKLCH(lightness, chroma, hue)
+	rgbColor = rgbFromFloats(r, g, b)
+	if ! inSRGBGamut(okLabToSRGB(lchToLab(lightness, chroma, hue))) {
+		err = &outOfGamutError{colorName, rgbColor}
+	}
+	return
+}
+
+
+/* Parse an RGB color: "255,128,0", "255 128 0", "1.0,0.5,0" or
+ * "100%,50%,0%". Components are either all 0-255 integers, all 0-1 fractions
+ * (if any has a decimal point) or all percentages. */
+func parseRGB(colorName string) (rgbColor color.RGBColor, err error) {
+	components, err := splitColorComponents(colorName, "rgb")
+	if err != nil {
+		return
+	}
+	if len(components) != 3 {
+		err = fmt.Errorf("expected 3 RGB components, got %d", len(components))
+		return
+	}
+
+	percents, decimals := 0, 0
+	for _, component := range components {
+		if strings.HasSuffix(component, "%") {
+			percents++
+		} else if strings.Contains(component, ".") {
+			decimals++
+		}
+	}
+	if percents != 0 && percents != 3 {
+		err = fmt.Errorf("RGB components must be all percentages or none: %q", colorName)
+		return
+	}
+
+	names := [3]string{"red component", "green component", "blue component"}
+	for i, component := range components {
+		if _, parseErr := parseNumber(strings.TrimSuffix(component, "%")); parseErr != nil {
+			err = fmt.Errorf("invalid %s %q", names[i], component)
+			return
+		}
+	}
+
+	/* Every component is a number, so from here on this is a bad RGB color
+	 * rather than some other color type. */
+	var values [3]float64
+	for i, name := range names {
+		switch {
+		case percents > 0:
+			values[i], err = parsePercent(components[i], name)
+		case decimals > 0:
+			values[i], err = parseFraction(components[i], name)
+		default:
+			var n int
+			n, err = strconv.Atoi(components[i])
+			if err != nil {
+				err = fmt.Errorf("invalid %s %q", name, components[i])
+			} else if n < 0 || n > 255 {
+				err = fmt.Errorf("%s %q out of range 0-255", name, components[i])
+			}
+			values[i] = float64(n) / 255
+		}
+		if err != nil {
+			err = &invalidColorError{err.Error()}
+			return
+		}
+	}
+
+	rgbColor = rgbFromFloats(values[0], values[1], values[2])
+	return
+}
+
+
+/* Parse a hex color: "ffee00", "fe0", "#ffee00" or "0xffee00". */
+func parseHex(colorName string) (rgbColor color.RGBColor, err error) {
+	hex := strings.ToLower(strings.TrimSpace(colorName))
+	hex = strings.TrimPrefix(hex, "#")
+	hex = strings.TrimPrefix(hex, "0x")
+
+	if len(hex) != 3 && len(hex) != 6 {
+		err = fmt.Errorf("hex color %q must have 3 or 6 hex digits", colorName)
+		return
+	}
+	n, parseErr := strconv.ParseUint(hex, 16, 32)
+	if parseErr != nil {
+		err = fmt.Errorf("invalid hex color %q", colorName)
+		return
+	}
+
+	if len(hex) == 3 {
+		rgbColor = color.RGB(uint8(n >> 8) * 17, uint8(n >> 4 & 0xf) * 17, uint8(n & 0xf) * 17, true)
+	} else {
+		rgbColor = color.RGB(uint8(n >> 16), uint8(n >> 8), uint8(n), true)
+	}
+	return
+}
+
+
+/* Prefixes that may come before an xterm color index, e.g. "ansi:208" or
+ * "color 208". */
+var ansiIndexPrefixes = []string{"ansi", "xterm", "colour", "color"}
+
+/* Parse an xterm color index below count (256 or 16) or an ANSI color name, as
+ * the color xterm shows by default. Accepts "208", "ansi:208", "color 208",
+ * the SGR parameters "5;208" or "38;5;208", and names like "brightred". */
+func parseANSI(colorName string, count int) (rgbColor color.RGBColor, err error) {
+	s := strings.ToLower(strings.TrimSpace(colorName))
+
+	name := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(s)
+	for i, ansiName := range ansi16Names {
+		if name == ansiName {
+			return xterm256Palette[i], nil
+		}
+	}
+
+	for _, prefix := range ansiIndexPrefixes {
+		if strings.HasPrefix(s, prefix) {
+			s = strings.TrimLeft(strings.TrimPrefix(s, prefix), ": ")
+			break
+		}
+	}
+	s = strings.TrimPrefix(s, "38;")
+	s = strings.TrimPrefix(s, "48;")
+	s = strings.TrimPrefix(s, "5;")
+
+	index, parseErr := strconv.Atoi(s)
+	if parseErr != nil {
+		err = fmt.Errorf("not an xterm color index or ANSI color name: %q", colorName)
+		return
+	}
+	if index < 0 || index >= count {
+		err = &invalidColorError{fmt.Sprintf("color index %q out of range 0-%d", colorName, count - 1)}
+		return
+	}
+	return xterm256Palette[index], nil
+}
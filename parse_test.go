@@ -0,0 +1,354 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+
+/* A parser case: the input, and the hex value it gives or "" if it is
+ * invalid. */
+type parseCase struct {
+	colorName string
+	hex       string
+}
+
+
+/* Run parse on every case, expecting the given hex value or an error. An out of
+ * gamut warning counts as an error here. */
+func testParser(t *testing.T, name string, parse func(string) (string, error), cases []parseCase) {
+	t.Helper()
+	for _, c := range cases {
+		hex, err := parse(c.colorName)
+		switch {
+		case len(c.hex) == 0 && err == nil:
+			t.Errorf("%s(%q) = #%s, want an error", name, c.colorName, hex)
+		case len(c.hex) > 0 && err != nil:
+			t.Errorf("%s(%q) failed: %s", name, c.colorName, err)
+		case len(c.hex) > 0 && hex != c.hex:
+			t.Errorf("%s(%q) = #%s, want #%s", name, c.colorName, hex, c.hex)
+		}
+	}
+}
+
+
+func TestParseHSL(t *testing.T) {
+	testParser(t, "parseHSL", func(s string) (string, error) {
+		rgbColor, err := parseHSL(s)
+		return rgbColor.Hex(), err
+	}, []parseCase{
+		{"0,100%,50%", "ff0000"},
+		{"120 100% 50%", "00ff00"},
+		{"hsl(240 100% 50%)", "0000ff"},
+		{"210 50 40", "336699"},
+		{"0,0,100", "ffffff"},
+		{"0,0,0", "000000"},
+		{"400,0,0", "000000"},
+		{"-120,100%,50%", "0000ff"},
+		{"90deg,100%,50%", "80ff00"},
+		{"0,101%,50%", ""},
+		{"0,50%,-1%", ""},
+		{"0,x,50%", ""},
+		{"0,0", ""},
+		{"nan,50%,50%", ""},
+		{"0,nan,50", ""},
+		{"inf,50%,50%", ""},
+		{"hsl(0 50% -inf%)", ""},
+	})
+}
+
+
+func TestParseHSV(t *testing.T) {
+	testParser(t, "parseHSV", func(s string) (string, error) {
+		rgbColor, err := parseHSV(s)
+		return rgbColor.Hex(), err
+	}, []parseCase{
+		{"0,100%,100%", "ff0000"},
+		{"hsv(120 100% 100%)", "00ff00"},
+		{"hsb(240,100%,50%)", "000080"},
+		{"0 0 0", "000000"},
+		{"0 0 100", "ffffff"},
+		{"0,101%,100%", ""},
+		{"0,0", ""},
+	})
+}
+
+
+func TestParseLab(t *testing.T) {
+	testParser(t, "parseLab", func(s string) (string, error) {
+		rgbColor, err := parseLab(s)
+		return rgbColor.Hex(), err
+	}, []parseCase{
+		{"lab(53.24 80.09 67.20)", "ff0000"},
+		{"100 0 0", "ffffff"},
+		{"0 0 0", "000000"},
+		{"lab(50% 40 0)", "b45a78"},
+		{"lab(50 x 0)", ""},
+		{"lab(50 0)", ""},
+		{"lab(50 nan 0)", ""},
+		{"lab(nan 0 0)", ""},
+	})
+
+	defer func(white whitePoint) { labWhitePoint = white }(labWhitePoint)
+	labWhitePoint = whiteD50
+	testParser(t, "parseLab with D50", func(s string) (string, error) {
+		rgbColor, err := parseLab(s)
+		return rgbColor.Hex(), err
+	}, []parseCase{
+		{"lab(50% 40 0)", "b35979"},
+	})
+}
+
+
+func TestParseLCH(t *testing.T) {
+	testParser(t, "parseLCH", func(s string) (string, error) {
+		rgbColor, err := parseLCH(s)
+		return rgbColor.Hex(), err
+	}, []parseCase{
+		{"lch(50 30 120)", "6d7d4a"},
+		{"lch(100 0 0)", "ffffff"},
+		{"0 0 0", "000000"},
+		{"lch(50 -1 0)", ""},
+		{"lch(50 30)", ""},
+	})
+}
+
+
+func TestParseOKLab(t *testing.T) {
+	testParser(t, "parseOKLab", func(s string) (string, error) {
+		rgbColor, err := parseOKLab(s)
+		return rgbColor.Hex(), err
+	}, []parseCase{
+		{"oklab(0.628 0.2249 0.1258)", "ff0000"},
+		{"oklab(100% 0 0)", "ffffff"},
+		{"0 0 0", "000000"},
+		{"oklab(0.5 0.1)", ""},
+	})
+}
+
+
+func TestParseOKLCH(t *testing.T) {
+	testParser(t, "parseOKLCH", func(s string) (string, error) {
+		rgbColor, err := parseOKLCH(s)
+		return rgbColor.Hex(), err
+	}, []parseCase{
+		{"oklch(62.8% 0.2577 29.23)", "ff0000"},
+		{"oklch(1 0 0)", "ffffff"},
+		{"0 0 0", "000000"},
+		{"0.7 -0.1 0", ""},
+		{"oklch(0.5 0.1)", ""},
+		{"oklch(0.5 0.1 nan)", ""},
+		{"oklch(0.5 inf 0)", ""},
+	})
+}
+
+
+/* Colors outside the sRGB gamut give the mapped color with an
+ * *outOfGamutError. */
+func TestGamutMapping(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		parse     func(string) (string, error)
+		colorName string
+		hex       string
+	}{
+		{"parseLab", func(s string) (string, error) { c, err := parseLab(s); return c.Hex(), err }, "lab(50 150 0)", "ff007e"},
+		{"parseLCH", func(s string) (string, error) { c, err := parseLCH(s); return c.Hex(), err }, "lch(54.29% 106.84 40.85)", "ff0500"},
+		{"parseOKLCH", func(s string) (string, error) { c, err := parseOKLCH(s); return c.Hex(), err }, "oklch(0.5 0.4 150)", "007c25"},
+		{"parseOKLCH", func(s string) (string, error) { c, err := parseOKLCH(s); return c.Hex(), err }, "oklch(0.63 0.26 29deg)", "ff0001"},
+	} {
+		hex, err := c.parse(c.colorName)
+		if ! isOutOfGamut(err) {
+			t.Errorf("%s(%q): got error %v, want an out of gamut error", c.name, c.colorName, err)
+		}
+		if hex != c.hex {
+			t.Errorf("%s(%q) = #%s, want #%s", c.name, c.colorName, hex, c.hex)
+		}
+	}
+}
+
+
+func TestParseRGB(t *testing.T) {
+	testParser(t, "parseRGB", func(s string) (string, error) {
+		rgbColor, err := parseRGB(s)
+		return rgbColor.Hex(), err
+	}, []parseCase{
+		{"0,0,0", "000000"},
+		{"1,0,0", "010000"},
+		{"255,255,255", "ffffff"},
+		{"255 128 0", "ff8000"},
+		{"rgb(255, 128, 0)", "ff8000"},
+		{"256,0,0", ""},
+		{"-1,0,0", ""},
+		{"300,300,300", ""},
+		{"0%,0%,0%", "000000"},
+		{"100%,100%,100%", "ffffff"},
+		{"100%,50%,0%", "ff8000"},
+		{"101%,0%,0%", ""},
+		{"50%,0,0", ""},
+		{"1.0,0,0", "ff0000"},
+		{"0.5,0.5,0.5", "808080"},
+		{"1.5,0,0", ""},
+		{"255,x,0", ""},
+		{"0%", ""},
+		{"100%", ""},
+		{"1.0", ""},
+		{"0,0", ""},
+		{"nan,0,0", ""},
+		{"0.5,NaN,0", ""},
+		{"inf%,0%,0%", ""},
+	})
+}
+
+
+/* Out of range RGB components are reported as invalid RGB, not tried as
+ * another color type. */
+func TestResolveExactColorInvalidRGB(t *testing.T) {
+	for _, c := range []struct {
+		colorName string
+		component string
+	}{
+		{"1.5,0,0", "red"},
+		{"0.5,1.2,0", "green"},
+		{"1.0,0.5,2", "blue"},
+		{"101%,0%,0%", "red"},
+		{"256,0,0", "red"},
+		{"0,0,-1", "blue"},
+	} {
+		resolved, err := resolveExactColor("", c.colorName)
+		if err == nil {
+			t.Errorf("resolveExactColor(%q) = #%s as %s, want an error", c.colorName, resolved.rgbColor.Hex(), resolved.colorType)
+			continue
+		}
+		if errorStatus(err) != STATUS_INVALID_COLOR || ! strings.Contains(err.Error(), c.component + " component") {
+			t.Errorf("resolveExactColor(%q): got error %q (status %d), want an invalid %s component", c.colorName, err, errorStatus(err), c.component)
+		}
+	}
+}
+
+
+/* An error in a color written with a parser's own function name stops
+ * auto-detection there. */
+func TestResolveExactColorFunctionalNotation(t *testing.T) {
+	for _, colorName := range []string{
+		"hsv(400 50% 50%)",
+		"hsb(0 x 50%)",
+		"lab(150 0 0)",
+		"lab(50 x 0)",
+		"lab(50 0)",
+		"lch(50 -1 0)",
+		"lch(50 30)",
+		"oklab(2 0 0)",
+		"oklab(0.5 x 0)",
+		"oklch(0.5 0.1)",
+		"oklch(0.5 -0.1 0)",
+	} {
+		resolved, err := resolveExactColor("", colorName)
+		if err == nil {
+			t.Errorf("resolveExactColor(%q) = #%s as %s, want an error", colorName, resolved.rgbColor.Hex(), resolved.colorType)
+		} else if errorStatus(err) != STATUS_INVALID_COLOR {
+			t.Errorf("resolveExactColor(%q): got error %q (status %d), want an invalid color", colorName, err, errorStatus(err))
+		}
+	}
+}
+
+
+func TestParseHex(t *testing.T) {
+	testParser(t, "parseHex", func(s string) (string, error) {
+		rgbColor, err := parseHex(s)
+		return rgbColor.Hex(), err
+	}, []parseCase{
+		{"000", "000000"},
+		{"#000", "000000"},
+		{"000000", "000000"},
+		{"0x000000", "000000"},
+		{"000001", "000001"},
+		{"#010101", "010101"},
+		{"#f80", "ff8800"},
+		{"FF8000", "ff8000"},
+		{"ffffff", "ffffff"},
+		{"0,0,0", ""},
+		{"0000", ""},
+		{"#ggg", ""},
+		{"1.0", ""},
+	})
+}
+
+
+/* Black and near-black are valid colors in every type that can write them,
+ * not mistaken for a failed parse. */
+func TestBlackIsValid(t *testing.T) {
+	for _, c := range []struct {
+		colorType string
+		colorName string
+		hex       string
+	}{
+		{"hex", "000", "000000"},
+		{"hex", "#000000", "000000"},
+		{"hex", "000001", "000001"},
+		{"rgb", "0,0,0", "000000"},
+		{"rgb", "0,0,1", "000001"},
+		{"rgb", "0%,0%,0%", "000000"},
+		{"rgb", "0.0,0.0,0.0", "000000"},
+		{"css", "#000", "000000"},
+		{"css", "rgb(0 0 0)", "000000"},
+		{"css", "black", "000000"},
+		{"hsl", "0,0%,0%", "000000"},
+		{"hsv", "0,0%,0%", "000000"},
+		{"lab", "0 0 0", "000000"},
+		{"oklab", "0 0 0", "000000"},
+		{"oklch", "0 0 0", "000000"},
+		{"web", "black", "000000"},
+		{"x11", "black", "000000"},
+		{"x11", "gray1", "030303"},
+	} {
+		rgbColor, _, err := transformColor(c.colorType, c.colorName)
+		if err != nil {
+			t.Errorf("transformColor(%q, %q) failed: %s", c.colorType, c.colorName, err)
+		} else if rgbColor.Hex() != c.hex {
+			t.Errorf("transformColor(%q, %q) = #%s, want #%s", c.colorType, c.colorName, rgbColor.Hex(), c.hex)
+		}
+	}
+}
+
+
+/* Black and near-black in every notation auto-detection should recognize. */
+func TestResolveExactColorBlack(t *testing.T) {
+	for _, c := range []struct {
+		colorName string
+		colorType string
+		hex       string
+	}{
+		{"black", "css", "000000"},
+		{"000", "hex", "000000"},
+		{"#000", "css", "000000"},
+		{"000000", "hex", "000000"},
+		{"0x000000", "hex", "000000"},
+		{"000001", "hex", "000001"},
+		{"0,0,0", "rgb", "000000"},
+		{"0 0 0", "rgb", "000000"},
+		{"0,0,1", "rgb", "000001"},
+		{"0%,0%,0%", "rgb", "000000"},
+		{"rgb(0 0 0)", "css", "000000"},
+		{"hsl(0 0% 0%)", "css", "000000"},
+		{"lab(0 0 0)", "lab", "000000"},
+		{"oklch(0 0 0)", "oklch", "000000"},
+		{"rgb:0/0/0", "xcolor", "000000"},
+		{"gray1", "x11", "030303"},
+	} {
+		resolved, err := resolveExactColor("", c.colorName)
+		if err != nil {
+			t.Errorf("resolveExactColor(%q) failed: %s", c.colorName, err)
+			continue
+		}
+		if resolved.rgbColor.Hex() != c.hex || resolved.colorType != c.colorType {
+			t.Errorf("resolveExactColor(%q) = #%s as %s, want #%s as %s", c.colorName, resolved.rgbColor.Hex(), resolved.colorType, c.hex, c.colorType)
+		}
+	}
+
+	for _, colorName := range []string{"blackk", "256,0,0", "#00"} {
+		if resolved, err := resolveExactColor("", colorName); err == nil {
+			t.Errorf("resolveExactColor(%q) = #%s as %s, want an error", colorName, resolved.rgbColor.Hex(), resolved.colorType)
+		}
+	}
+}
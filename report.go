@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+
+/* Machine-readable output formats accepted by -format. */
+var reportFormats = []string{"json", "yaml", "tsv"}
+
+
+/* Color spaces included in a report, in output order. */
+var reportSpaces = []string{"rgb", "hsl", "hsv", "lab", "oklab", "oklch", "cmyk"}
+
+
+var statusNames = map[int]string{
+	STATUS_UNKNOWN_COLORTYPE: "STATUS_UNKNOWN_COLORTYPE",
+	STATUS_INVALID_COLOR:     "STATUS_INVALID_COLOR",
+	STATUS_NOT_IMPLEMENTED:   "STATUS_NOT_IMPLEMENTED",
+}
+
+
+/* A color, or the error it produced, for -format json|yaml|tsv. */
+type colorReport struct {
+	Input      string               `json:"input"`
+	Valid      bool                 `json:"valid"`
+	Type       string               `json:"type,omitempty"`
+	OutputType string               `json:"output_type,omitempty"`
+	Hex        string               `json:"hex,omitempty"`
+	Alpha      *float64             `json:"alpha,omitempty"`
+	Components map[string][]float64 `json:"components,omitempty"`
+	SGR        *sgrReport           `json:"sgr,omitempty"`
+	Warning    string               `json:"warning,omitempty"`
+	Error      string               `json:"error,omitempty"`
+	Status     int                  `json:"status,omitempty"`
+	StatusName string               `json:"status_name,omitempty"`
+}
+
+
+func newColorReport(colorName string, resolved resolvedColor) colorReport {
+	rgbColor := resolved.rgbColor
+	r, g, b := rgbToFloats(rgbColor)
+
+	components := map[string][]float64{}
+	add := func(space string, values ...float64) {
+		for i, v := range values {
+			values[i] = math.Round(v * 10000) / 10000
+		}
+		components[space] = values
+	}
+	add("rgb", float64(rgbColor[0]), float64(rgbColor[1]), float64(rgbColor[2]))
+	add("hsl", percentComponents(rgbToHSL(r, g, b))...)
+	add("hsv", percentComponents(rgbToHSV(r, g, b))...)
+	l, a, bb := rgbToLabWhite(rgbColor, labWhitePoint)
+	add("lab", l, a, bb)
+	l, a, bb = srgbToOKLab(r, g, b)
+	add("oklab", l, a, bb)
+	lightness, chroma, hue := labToLCH(l, a, bb)
+	add("oklch", lightness, chroma, hue)
+	c, m, y, k := rgbToCMYK(r, g, b)
+	add("cmyk", c * 100, m * 100, y * 100, k * 100)
+
+	alpha := math.Round(resolved.alpha * 10000) / 10000
+
+	report := colorReport{
+		Input:      colorName,
+		Valid:      true,
+		Type:       resolved.colorType,
+		OutputType: resolved.colorOutputType,
+		Hex:        "#" + rgbColor.Hex(),
+		Alpha:      &alpha,
+		Components: components,
+		SGR:        resolved.sgr,
+	}
+	if resolved.warning != nil {
+		report.Warning = resolved.warning.Error()
+	}
+	return report
+}
+
+
+/* Hue as is, and the other two HSL/HSV components as percentages. */
+func percentComponents(hue, x, y float64) []float64 {
+	return []float64{hue, x * 100, y * 100}
+}
+
+
+func newErrorReport(colorName string, err error) colorReport {
+	status := errorStatus(err)
+	return colorReport{
+		Input:      colorName,
+		Error:      err.Error(),
+		Status:     status,
+		StatusName: statusNames[status],
+	}
+}
+
+
+func printReport(format string, report colorReport) {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(report)
+	case "yaml":
+		fmt.Print(reportYAML(report))
+	case "tsv":
+		/* the header is printed once, by main */
+		fmt.Println(strings.Join(reportTSVRow(report), "\t"))
+	}
+}
+
+
+/* A YAML document, written by hand to keep the fields in a fixed order. */
+func reportYAML(report colorReport) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	field := func(key string, value string) {
+		fmt.Fprintf(&sb, "%s: %s\n", key, value)
+	}
+	field("input", yamlString(report.Input))
+	field("valid", strconv.FormatBool(report.Valid))
+	if report.Valid {
+		field("type", yamlString(report.Type))
+		field("output_type", yamlString(report.OutputType))
+		field("hex", yamlString(report.Hex))
+		field("alpha", strconv.FormatFloat(*report.Alpha, 'f', -1, 64))
+		sb.WriteString("components:\n")
+		for _, space := range reportSpaces {
+			fmt.Fprintf(&sb, "  %s: [%s]\n", space, joinNumbers(report.Components[space], ", "))
+		}
+	}
+	if report.SGR != nil {
+		sb.WriteString("sgr:\n")
+		fmt.Fprintf(&sb, "  target: %s\n", yamlString(report.SGR.Target))
+		fmt.Fprintf(&sb, "  code: %s\n", yamlString(report.SGR.Code))
+		if len(report.SGR.Attributes) > 0 {
+			attributes := make([]string, len(report.SGR.Attributes))
+			for i, attribute := range report.SGR.Attributes {
+				attributes[i] = yamlString(attribute)
+			}
+			fmt.Fprintf(&sb, "  attributes: [%s]\n", strings.Join(attributes, ", "))
+		}
+	}
+	if len(report.Warning) > 0 {
+		field("warning", yamlString(report.Warning))
+	}
+	if len(report.Error) > 0 {
+		field("error", yamlString(report.Error))
+		field("status", strconv.Itoa(report.Status))
+		field("status_name", report.StatusName)
+	}
+	return sb.String()
+}
+
+
+/* JSON strings are valid YAML double-quoted strings. */
+func yamlString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+
+func joinNumbers(values []float64, sep string) string {
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strings.Join(formatted, sep)
+}
+
+
+func reportTSVHeader() []string {
+	header := []string{"input", "valid", "type", "output_type", "hex", "alpha"}
+	header = append(header, reportSpaces...)
+	header = append(header, "sgr_target", "sgr_code", "sgr_attributes")
+	return append(header, "warning", "error", "status", "status_name")
+}
+
+
+/* Components are comma separated within their column. Tabs and newlines in
+ * strings are replaced with spaces. */
+func reportTSVRow(report colorReport) []string {
+	clean := func(s string) string {
+		return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(s)
+	}
+	row := []string{clean(report.Input), strconv.FormatBool(report.Valid), report.Type, report.OutputType, report.Hex, ""}
+	if report.Alpha != nil {
+		row[5] = strconv.FormatFloat(*report.Alpha, 'f', -1, 64)
+	}
+	for _, space := range reportSpaces {
+		row = append(row, joinNumbers(report.Components[space], ","))
+	}
+	if report.SGR != nil {
+		row = append(row, report.SGR.Target, report.SGR.Code, strings.Join(report.SGR.Attributes, ","))
+	} else {
+		row = append(row, "", "", "")
+	}
+	status := ""
+	if report.Status != 0 {
+		status = strconv.Itoa(report.Status)
+	}
+	return append(row, clean(report.Warning), clean(report.Error), status, report.StatusName)
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+
+/* Ways of writing the escape character in a pasted SGR sequence. */
+var escapeNotations = strings.NewReplacer(
+	`\e`, "\x1b",
+	`\E`, "\x1b",
+	`\x1b`, "\x1b",
+	`\x1B`, "\x1b",
+	`\033`, "\x1b",
+	`\u001b`, "\x1b",
+	`\u001B`, "\x1b",
+	`^[`, "\x1b",
+)
+
+var sgrPattern = regexp.MustCompile("\x1b\\[([0-9;:]*)m")
+
+
+/* Names of the SGR attributes that are not colors. */
+var sgrAttributes = map[int]string{
+	0:  "reset",
+	1:  "bold",
+	2:  "dim",
+	3:  "italic",
+	4:  "underline",
+	5:  "blink",
+	6:  "rapid blink",
+	7:  "reverse",
+	8:  "hidden",
+	9:  "strikethrough",
+	21: "double underline",
+	22: "normal intensity",
+	23: "not italic",
+	24: "not underlined",
+	25: "not blinking",
+	27: "not reversed",
+	28: "not hidden",
+	29: "not strikethrough",
+	39: "default foreground",
+	49: "default background",
+	53: "overline",
+	55: "not overlined",
+	59: "default underline color",
+}
+
+
+/* A color set by an SGR sequence: its target ("fg", "bg" or "underline") and
+ * the parameters that set it, e.g. "38;5;208". */
+type sgrColor struct {
+	target   string
+	code     string
+	rgbColor color.RGBColor
+}
+
+
+/* The SGR details of a color in a report: the sgrColor, and the attributes set
+ * by the same sequence. */
+type sgrReport struct {
+	Target     string   `json:"target"`
+	Code       string   `json:"code"`
+	Attributes []string `json:"attributes,omitempty"`
+}
+
+
+func (c sgrColor) label() string {
+	return fmt.Sprintf("%s %s", c.target, c.code)
+}
+
+
+/* Whether colorName contains an SGR sequence, with the escape character
+ * written literally or as \e, \x1b, \033 or ^[. */
+func isSGR(colorName string) bool {
+	return sgrPattern.MatchString(escapeNotations.Replace(colorName))
+}
+
+
+/* Decode every SGR sequence in colorName into the colors and attributes it
+ * sets, in order. Text between the sequences is ignored. 16 and 256 colors are
+ * given xterm's default values. */
+func parseSGR(colorName string) (colors []sgrColor, attributes []string, err error) {
+	sequences := sgrPattern.FindAllStringSubmatch(escapeNotations.Replace(colorName), -1)
+	if len(sequences) == 0 {
+		err = fmt.Errorf("no SGR sequence in %q", colorName)
+		return
+	}
+
+	for _, sequence := range sequences {
+		params, parseErr := sgrParameters(sequence[1])
+		if parseErr != nil {
+			err = &invalidColorError{fmt.Sprintf("invalid SGR sequence %q: %s", sequence[0], parseErr)}
+			return
+		}
+
+		for i := 0; i < len(params); i++ {
+			p := params[i]
+			switch {
+			case p >= 30 && p <= 37, p >= 90 && p <= 97:
+				colors = append(colors, sgrColor{"fg", strconv.Itoa(p), xterm256Palette[ansiIndex(p, 30, 90)]})
+			case p >= 40 && p <= 47, p >= 100 && p <= 107:
+				colors = append(colors, sgrColor{"bg", strconv.Itoa(p), xterm256Palette[ansiIndex(p, 40, 100)]})
+			case p == 38, p == 48, p == 58:
+				c, n, extErr := sgrExtendedColor(params[i:])
+				if extErr != nil {
+					err = &invalidColorError{fmt.Sprintf("invalid SGR sequence %q: %s", sequence[0], extErr)}
+					return
+				}
+				c.target = map[int]string{38: "fg", 48: "bg", 58: "underline"}[p]
+				colors = append(colors, c)
+				i += n - 1
+			default:
+				name, ok := sgrAttributes[p]
+				if ! ok {
+					name = fmt.Sprintf("attribute %d", p)
+				}
+				attributes = append(attributes, name)
+			}
+		}
+	}
+	return
+}
+
+
+/* The numbers in an SGR parameter string. Colon separated subparameters
+ * ("38:2::255:128:0") are flattened into the semicolon form, dropping the
+ * color space id. Empty parameters are 0. */
+func sgrParameters(s string) (params []int, err error) {
+	for _, param := range strings.Split(s, ";") {
+		subparams := strings.Split(param, ":")
+		if len(subparams) == 6 && subparams[1] == "2" {
+			subparams = append(subparams[:2], subparams[3:]...)
+		}
+		for _, subparam := range subparams {
+			if len(subparam) == 0 {
+				params = append(params, 0)
+				continue
+			}
+			n, parseErr := strconv.Atoi(subparam)
+			if parseErr != nil {
+				return nil, fmt.Errorf("bad parameter %q", subparam)
+			}
+			params = append(params, n)
+		}
+	}
+	return
+}
+
+
+/* The index of an ANSI color code, given the codes of its normal and bright
+ * black. */
+func ansiIndex(code int, normal int, bright int) int {
+	if code >= bright {
+		return code - bright + 8
+	}
+	return code - normal
+}
+
+
+/* The color set by 38, 48 or 58 at the start of params, as "5;n" or
+ * "2;r;g;b", and the number of parameters it used. */
+func sgrExtendedColor(params []int) (c sgrColor, n int, err error) {
+	if len(params) < 2 {
+		err = fmt.Errorf("%d must be followed by 5 or 2", params[0])
+		return
+	}
+	switch params[1] {
+	case 5:
+		if len(params) < 3 || params[2] > 255 {
+			err = fmt.Errorf("%d;5 must be followed by a color index 0-255", params[0])
+			return
+		}
+		c.code = fmt.Sprintf("%d;5;%d", params[0], params[2])
+		c.rgbColor = xterm256Palette[params[2]]
+		return c, 3, nil
+	case 2:
+		if len(params) < 5 || params[2] > 255 || params[3] > 255 || params[4] > 255 {
+			err = fmt.Errorf("%d;2 must be followed by three components 0-255", params[0])
+			return
+		}
+		c.code = fmt.Sprintf("%d;2;%d;%d;%d", params[0], params[2], params[3], params[4])
+		c.rgbColor = color.RGB(uint8(params[2]), uint8(params[3]), uint8(params[4]), true)
+		return c, 5, nil
+	}
+	err = fmt.Errorf("%d must be followed by 5 or 2, not %d", params[0], params[1])
+	return
+}
+
+
+/* The one color set by an SGR sequence, for places that take a single color
+ * such as -fg and -bg. */
+func parseSGRColor(colorName string) (rgbColor color.RGBColor, err error) {
+	colors, _, err := parseSGR(colorName)
+	if err != nil {
+		return
+	}
+	if len(colors) != 1 {
+		err = &invalidColorError{fmt.Sprintf("SGR sequence %q sets %d colors, expected 1", colorName, len(colors))}
+		return
+	}
+	return colors[0].rgbColor, nil
+}
+
+
+/* Display the attributes and every color set by an SGR sequence, each color
+ * as a swatch labelled with its target and parameters. */
+func showSGR(colorName string, where string, opts options) (status int) {
+	colors, attributes, err := parseSGR(colorName)
+	if err != nil {
+		reportError(colorName, where, err, opts)
+		return errorStatus(err)
+	}
+
+	if opts.outputFormat == "text" && len(attributes) > 0 {
+		fmt.Println("attributes:", strings.Join(attributes, ", "))
+	}
+	opts.swatch = true
+	for _, c := range colors {
+		resolved := resolvedColor{
+			colorName:       colorName,
+			rgbColor:        c.rgbColor,
+			alpha:           1,
+			colorType:       "sgr",
+			colorOutputType: "rgb",
+			sgr:             &sgrReport{c.target, c.code, attributes},
+		}
+		displayColor(colorName, c.label(), resolved, opts)
+	}
+	return 0
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+
+func TestSGRParameters(t *testing.T) {
+	for _, c := range []struct {
+		s      string
+		params string
+	}{
+		{"", "[0]"},
+		{"0", "[0]"},
+		{"1;31", "[1 31]"},
+		{"1;;4", "[1 0 4]"},
+		{"38;5;208", "[38 5 208]"},
+		{"38:5:208", "[38 5 208]"},
+		{"38:2::255:128:0", "[38 2 255 128 0]"},
+		{"38:2:255:128:0", "[38 2 255 128 0]"},
+		{"1;x", ""},
+	} {
+		params, err := sgrParameters(c.s)
+		switch {
+		case len(c.params) == 0 && err == nil:
+			t.Errorf("sgrParameters(%q) = %v, want an error", c.s, params)
+		case len(c.params) > 0 && err != nil:
+			t.Errorf("sgrParameters(%q) failed: %s", c.s, err)
+		case len(c.params) > 0 && fmt.Sprint(params) != c.params:
+			t.Errorf("sgrParameters(%q) = %v, want %s", c.s, params, c.params)
+		}
+	}
+}
+
+
+func TestSGRExtendedColor(t *testing.T) {
+	for _, c := range []struct {
+		params []int
+		code   string
+		hex    string
+		n      int
+	}{
+		{[]int{38, 5, 208}, "38;5;208", "ff8700", 3},
+		{[]int{48, 5, 0, 1}, "48;5;0", "000000", 3},
+		{[]int{58, 2, 255, 128, 0}, "58;2;255;128;0", "ff8000", 5},
+		{[]int{38, 2, 0, 0, 0, 4}, "38;2;0;0;0", "000000", 5},
+		{[]int{38}, "", "", 0},
+		{[]int{38, 5}, "", "", 0},
+		{[]int{38, 5, 256}, "", "", 0},
+		{[]int{48, 2, 1, 2}, "", "", 0},
+		{[]int{38, 2, 256, 0, 0}, "", "", 0},
+		{[]int{38, 3, 1}, "", "", 0},
+	} {
+		sgr, n, err := sgrExtendedColor(c.params)
+		switch {
+		case len(c.code) == 0 && err == nil:
+			t.Errorf("sgrExtendedColor(%v) = %q, want an error", c.params, sgr.code)
+		case len(c.code) > 0 && err != nil:
+			t.Errorf("sgrExtendedColor(%v) failed: %s", c.params, err)
+		case len(c.code) > 0 && (sgr.code != c.code || sgr.rgbColor.Hex() != c.hex || n != c.n):
+			t.Errorf("sgrExtendedColor(%v) = %q #%s using %d, want %q #%s using %d", c.params, sgr.code, sgr.rgbColor.Hex(), n, c.code, c.hex, c.n)
+		}
+	}
+}
+
+
+func TestParseSGR(t *testing.T) {
+	for _, c := range []struct {
+		colorName  string
+		colors     string
+		attributes string
+	}{
+		{`\e[1;31;42m`, "fg 31 #cd0000, bg 42 #00cd00", "bold"},
+		{"\x1b[38;5;208mtext\x1b[0m", "fg 38;5;208 #ff8700", "reset"},
+		{`^[[4;58:2::1:2:3m`, "underline 58;2;1;2;3 #010203", "underline"},
+		{`\033[102m`, "bg 102 #00ff00", ""},
+		{`\x1b[1m`, "", "bold"},
+	} {
+		colors, attributes, err := parseSGR(c.colorName)
+		if err != nil {
+			t.Errorf("parseSGR(%q) failed: %s", c.colorName, err)
+			continue
+		}
+		var labels []string
+		for _, sgr := range colors {
+			labels = append(labels, fmt.Sprintf("%s #%s", sgr.label(), sgr.rgbColor.Hex()))
+		}
+		if got := strings.Join(labels, ", "); got != c.colors {
+			t.Errorf("parseSGR(%q) colors = %q, want %q", c.colorName, got, c.colors)
+		}
+		if got := strings.Join(attributes, ", "); got != c.attributes {
+			t.Errorf("parseSGR(%q) attributes = %q, want %q", c.colorName, got, c.attributes)
+		}
+	}
+
+	for _, colorName := range []string{"31", `\e[38;5;300m`, `\e[38;2;1m`} {
+		if colors, _, err := parseSGR(colorName); err == nil {
+			t.Errorf("parseSGR(%q) = %v, want an error", colorName, colors)
+		}
+	}
+}
+
+
+
+/* Reports of SGR colors say which color of the sequence they are. */
+func TestSGRColorReport(t *testing.T) {
+	colors, attributes, err := parseSGR(`\e[1;31;102m`)
+	if err != nil {
+		t.Fatalf("parseSGR failed: %s", err)
+	}
+	c := colors[1]
+	report := newColorReport(`\e[1;31;102m`, resolvedColor{rgbColor: c.rgbColor, colorType: "sgr", sgr: &sgrReport{c.target, c.code, attributes}})
+
+	encoded, err := json.Marshal(report.SGR)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %s", err)
+	}
+	if want := `{"target":"bg","code":"102","attributes":["bold"]}`; string(encoded) != want {
+		t.Errorf("sgr = %s, want %s", encoded, want)
+	}
+	if row, header := reportTSVRow(report), reportTSVHeader(); len(row) != len(header) {
+		t.Errorf("TSV row has %d fields, header %d", len(row), len(header))
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gookit/color"
+)
+
+
+/* Glyph styles accepted by -swatch-style:
+ *   cells:      spaces on the color's background
+ *   blocks:     full blocks in the color's foreground
+ *   halfblocks: upper half blocks, so that height counts half lines */
+var swatchStyles = []string{"cells", "blocks", "halfblocks"}
+
+/* Label placements accepted by -label. */
+var labelPlacements = []string{"beside", "inside", "none"}
+
+
+type swatchOptions struct {
+	width  int
+	height int
+	style  string
+	label  string
+}
+
+
+/* Lines of a swatch of rgbColor, with the label beside or inside it on the
+ * middle line. An inside label is black or white, whichever is more readable,
+ * and widens the swatch if it does not fit. */
+func renderSwatch(rgbColor color.RGBColor, label string, swatch swatchOptions) (lines []string) {
+	bg := rgbColor
+	fg := color.RGB(rgbColor[0], rgbColor[1], rgbColor[2])
+
+	width := swatch.width
+	labelWidth := utf8.RuneCountInString(label)
+	if swatch.label == "inside" && labelWidth + 2 > width {
+		width = labelWidth + 2
+	}
+
+	rows := swatch.height
+	if swatch.style == "halfblocks" {
+		rows = (swatch.height + 1) / 2
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	middle := (rows - 1) / 2
+
+	for row := 0; row < rows; row++ {
+		var line string
+		switch {
+		case row == middle && swatch.label == "inside":
+			padLeft := (width - labelWidth) / 2
+			padded := strings.Repeat(" ", padLeft) + label + strings.Repeat(" ", width - labelWidth - padLeft)
+			line = paint(padded, readableTextColor(bg), bg)
+		case swatch.style == "blocks":
+			line = paint(strings.Repeat("█", width), fg)
+		case swatch.style == "halfblocks" && row == rows - 1 && swatch.height % 2 == 1:
+			/* only the upper half of the last line */
+			line = paint(strings.Repeat("▀", width), fg)
+		case swatch.style == "halfblocks":
+			line = paint(strings.Repeat("▀", width), fg, bg)
+		default:
+			line = paint(strings.Repeat(" ", width), bg)
+		}
+
+		if row == middle && swatch.label == "beside" && len(label) > 0 {
+			line = fmt.Sprintf("%s %s", line, label)
+		}
+		lines = append(lines, line)
+	}
+	return
+}
+
+
+func printSwatch(rgbColor color.RGBColor, label string, swatch swatchOptions) {
+	for _, line := range renderSwatch(rgbColor, label, swatch) {
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/xo/terminfo"
+)
+
+
+/* Modes accepted by -color:
+ *   auto:      detect the terminal's color level; no color when NO_COLOR is
+ *              set or standard output is not a terminal
+ *   always:    detect the color level even when piped or NO_COLOR is set
+ *   never:     plain text
+ *   256, 16, truecolor: force that color level */
+var colorModes = []string{"auto", "always", "never", "256", "16", "truecolor"}
+
+
+/* The color level the terminal supports, going by COLORTERM and TERM's
+ * max_colors in terminfo. Pipes and NO_COLOR are not considered here. */
+func detectColorLevel() terminfo.ColorLevel {
+	term := os.Getenv("TERM")
+	if term == "dumb" {
+		return terminfo.ColorLevelNone
+	}
+
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	if strings.Contains(colorTerm, "truecolor") || strings.Contains(colorTerm, "24bit") {
+		return terminfo.ColorLevelMillions
+	}
+
+	if len(term) == 0 {
+		return terminfo.ColorLevelNone
+	}
+	ti, err := terminfo.Load(term)
+	if err != nil {
+		/* an unknown terminal that claims color, e.g. COLORTERM=yes */
+		if len(colorTerm) > 0 {
+			return terminfo.ColorLevelBasic
+		}
+		return terminfo.ColorLevelNone
+	}
+	colors := ti.Nums[terminfo.MaxColors]
+	switch {
+	case colors >= 1 << 24:
+		return terminfo.ColorLevelMillions
+	case colors >= 256:
+		return terminfo.ColorLevelHundreds
+	case colors >= 8:
+		return terminfo.ColorLevelBasic
+	}
+	return terminfo.ColorLevelNone
+}
+
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode() & os.ModeCharDevice != 0
+}
+
+
+/* Set the color level that all output is rendered at, for a -color mode. */
+func setColorMode(mode string) error {
+	var level terminfo.ColorLevel
+	switch mode {
+	case "auto":
+		if len(os.Getenv("NO_COLOR")) > 0 || ! isTerminal(os.Stdout) {
+			level = terminfo.ColorLevelNone
+		} else {
+			level = detectColorLevel()
+		}
+	case "always":
+		level = detectColorLevel()
+		if level == terminfo.ColorLevelNone {
+			level = terminfo.ColorLevelMillions
+		}
+	case "never":
+		level = terminfo.ColorLevelNone
+	case "256":
+		level = terminfo.ColorLevelHundreds
+	case "16":
+		level = terminfo.ColorLevelBasic
+	case "truecolor":
+		level = terminfo.ColorLevelMillions
+	default:
+		return fmt.Errorf("Unknown color mode: %s", mode)
+	}
+
+	/* gookit/color disables itself when NO_COLOR is set, whatever the level */
+	color.Enable = level != terminfo.ColorLevelNone
+	color.ForceSetColorLevel(level)
+	return nil
+}
+
+
+/* SGR parameters for rgbColor as a foreground or background, at the current
+ * color level: the color itself in truecolor, otherwise its closest xterm 256
+ * or ANSI color. */
+func colorCode(rgbColor color.RGBColor) string {
+	return colorCodeAt(rgbColor, color.TermColorLevel())
+}
+
+
+/* Like colorCode, at the given color level. */
+func colorCodeAt(rgbColor color.RGBColor, level terminfo.ColorLevel) string {
+	isBg := rgbColor[3] == color.AsBg
+	switch level {
+	case terminfo.ColorLevelMillions:
+		return rgbColor.String()
+	case terminfo.ColorLevelHundreds:
+		index, _ := rgbToXterm256(rgbColor)
+		if isBg {
+			return fmt.Sprintf(color.TplBg256, index)
+		}
+		return fmt.Sprintf(color.TplFg256, index)
+	case terminfo.ColorLevelBasic:
+		index, _ := rgbToANSI16(rgbColor)
+		if isBg {
+			return ansi16Background(index).String()
+		}
+		return ansi16Foreground(index).String()
+	}
+	return ""
+}
+
+
+/* s in the given foreground and/or background colors, rendered at the current
+ * color level. Plain s when color is off. */
+func paint(s string, colors ...color.RGBColor) string {
+	return paintAt(color.TermColorLevel(), s, colors...)
+}
+
+
+/* Like paint, at the given color level, or the current one if that is lower:
+ * a forced -color level is never exceeded. */
+func paintAt(level terminfo.ColorLevel, s string, colors ...color.RGBColor) string {
+	if current := color.TermColorLevel(); current < level {
+		level = current
+	}
+	codes := make([]string, 0, len(colors))
+	for _, rgbColor := range colors {
+		if code := colorCodeAt(rgbColor, level); len(code) > 0 {
+			codes = append(codes, code)
+		}
+	}
+	return color.RenderString(strings.Join(codes, ";"), s)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"github.com/gookit/color"
+)
+
+
+/* https://www.w3.org/TR/css-color-4/#named-colors
+ *
+ * "transparent" is rgba(0, 0, 0, 0); colors have no alpha channel here, so it is
+ * shown as black. */
+var webColors map[string]color.RGBColor = map[string]color.RGBColor{
+	"aliceblue"           : color.RGB(240,248,255,true),
+	"antiquewhite"        : color.RGB(250,235,215,true),
+	"aqua"                : color.RGB(0,255,255,true),
+	"aquamarine"          : color.RGB(127,255,212,true),
+	"azure"               : color.RGB(240,255,255,true),
+	"beige"               : color.RGB(245,245,220,true),
+	"bisque"              : color.RGB(255,228,196,true),
+	"black"               : color.RGB(0,0,0,true),
+	"blanchedalmond"      : color.RGB(255,235,205,true),
+	"blue"                : color.RGB(0,0,255,true),
+	"blueviolet"          : color.RGB(138,43,226,true),
+	"brown"               : color.RGB(165,42,42,true),
+	"burlywood"           : color.RGB(222,184,135,true),
+	"cadetblue"           : color.RGB(95,158,160,true),
+	"chartreuse"          : color.RGB(127,255,0,true),
+	"chocolate"           : color.RGB(210,105,30,true),
+	"coral"               : color.RGB(255,127,80,true),
+	"cornflowerblue"      : color.RGB(100,149,237,true),
+	"cornsilk"            : color.RGB(255,248,220,true),
+	"crimson"             : color.RGB(220,20,60,true),
+	"cyan"                : color.RGB(0,255,255,true),
+	"darkblue"            : color.RGB(0,0,139,true),
+	"darkcyan"            : color.RGB(0,139,139,true),
+	"darkgoldenrod"       : color.RGB(184,134,11,true),
+	"darkgray"            : color.RGB(169,169,169,true),
+	"darkgreen"           : color.RGB(0,100,0,true),
+	"darkgrey"            : color.RGB(169,169,169,true),
+	"darkkhaki"           : color.RGB(189,183,107,true),
+	"darkmagenta"         : color.RGB(139,0,139,true),
+	"darkolivegreen"      : color.RGB(85,107,47,true),
+	"darkorange"          : color.RGB(255,140,0,true),
+	"darkorchid"          : color.RGB(153,50,204,true),
+	"darkred"             : color.RGB(139,0,0,true),
+	"darksalmon"          : color.RGB(233,150,122,true),
+	"darkseagreen"        : color.RGB(143,188,143,true),
+	"darkslateblue"       : color.RGB(72,61,139,true),
+	"darkslategray"       : color.RGB(47,79,79,true),
+	"darkslategrey"       : color.RGB(47,79,79,true),
+	"darkturquoise"       : color.RGB(0,206,209,true),
+	"darkviolet"          : color.RGB(148,0,211,true),
+	"deeppink"            : color.RGB(255,20,147,true),
+	"deepskyblue"         : color.RGB(0,191,255,true),
+	"dimgray"             : color.RGB(105,105,105,true),
+	"dimgrey"             : color.RGB(105,105,105,true),
+	"dodgerblue"          : color.RGB(30,144,255,true),
+	"firebrick"           : color.RGB(178,34,34,true),
+	"floralwhite"         : color.RGB(255,250,240,true),
+	"forestgreen"         : color.RGB(34,139,34,true),
+	"fuchsia"             : color.RGB(255,0,255,true),
+	"gainsboro"           : color.RGB(220,220,220,true),
+	"ghostwhite"          : color.RGB(248,248,255,true),
+	"gold"                : color.RGB(255,215,0,true),
+	"goldenrod"           : color.RGB(218,165,32,true),
+	"gray"                : color.RGB(128,128,128,true),
+	"green"               : color.RGB(0,128,0,true),
+	"greenyellow"         : color.RGB(173,255,47,true),
+	"grey"                : color.RGB(128,128,128,true),
+	"honeydew"            : color.RGB(240,255,240,true),
+	"hotpink"             : color.RGB(255,105,180,true),
+	"indianred"           : color.RGB(205,92,92,true),
+	"indigo"              : color.RGB(75,0,130,true),
+	"ivory"               : color.RGB(255,255,240,true),
+	"khaki"               : color.RGB(240,230,140,true),
+	"lavender"            : color.RGB(230,230,250,true),
+	"lavenderblush"       : color.RGB(255,240,245,true),
+	"lawngreen"           : color.RGB(124,252,0,true),
+	"lemonchiffon"        : color.RGB(255,250,205,true),
+	"lightblue"           : color.RGB(173,216,230,true),
+	"lightcoral"          : color.RGB(240,128,128,true),
+	"lightcyan"           : color.RGB(224,255,255,true),
+	"lightgoldenrodyellow": color.RGB(250,250,210,true),
+	"lightgray"           : color.RGB(211,211,211,true),
+	"lightgreen"          : color.RGB(144,238,144,true),
+	"lightgrey"           : color.RGB(211,211,211,true),
+	"lightpink"           : color.RGB(255,182,193,true),
+	"lightsalmon"         : color.RGB(255,160,122,true),
+	"lightseagreen"       : color.RGB(32,178,170,true),
+	"lightskyblue"        : color.RGB(135,206,250,true),
+	"lightslategray"      : color.RGB(119,136,153,true),
+	"lightslategrey"      : color.RGB(119,136,153,true),
+	"lightsteelblue"      : color.RGB(176,196,222,true),
+	"lightyellow"         : color.RGB(255,255,224,true),
+	"lime"                : color.RGB(0,255,0,true),
+	"limegreen"           : color.RGB(50,205,50,true),
+	"linen"               : color.RGB(250,240,230,true),
+	"magenta"             : color.RGB(255,0,255,true),
+	"maroon"              : color.RGB(128,0,0,true),
+	"mediumaquamarine"    : color.RGB(102,205,170,true),
+	"mediumblue"          : color.RGB(0,0,205,true),
+	"mediumorchid"        : color.RGB(186,85,211,true),
+	"mediumpurple"        : color.RGB(147,112,219,true),
+	"mediumseagreen"      : color.RGB(60,179,113,true),
+	"mediumslateblue"     : color.RGB(123,104,238,true),
+	"mediumspringgreen"   : color.RGB(0,250,154,true),
+	"mediumturquoise"     : color.RGB(72,209,204,true),
+	"mediumvioletred"     : color.RGB(199,21,133,true),
+	"midnightblue"        : color.RGB(25,25,112,true),
+	"mintcream"           : color.RGB(245,255,250,true),
+	"mistyrose"           : color.RGB(255,228,225,true),
+	"moccasin"            : color.RGB(255,228,181,true),
+	"navajowhite"         : color.RGB(255,222,173,true),
+	"navy"                : color.RGB(0,0,128,true),
+	"oldlace"             : color.RGB(253,245,230,true),
+	"olive"               : color.RGB(128,128,0,true),
+	"olivedrab"           : color.RGB(107,142,35,true),
+	"orange"              : color.RGB(255,165,0,true),
+	"orangered"           : color.RGB(255,69,0,true),
+	"orchid"              : color.RGB(218,112,214,true),
+	"palegoldenrod"       : color.RGB(238,232,170,true),
+	"palegreen"           : color.RGB(152,251,152,true),
+	"paleturquoise"       : color.RGB(175,238,238,true),
+	"palevioletred"       : color.RGB(219,112,147,true),
+	"papayawhip"          : color.RGB(255,239,213,true),
+	"peachpuff"           : color.RGB(255,218,185,true),
+	"peru"                : color.RGB(205,133,63,true),
+	"pink"                : color.RGB(255,192,203,true),
+	"plum"                : color.RGB(221,160,221,true),
+	"powderblue"          : color.RGB(176,224,230,true),
+	"purple"              : color.RGB(128,0,128,true),
+	"rebeccapurple"       : color.RGB(102,51,153,true),
+	"red"                 : color.RGB(255,0,0,true),
+	"rosybrown"           : color.RGB(188,143,143,true),
+	"royalblue"           : color.RGB(65,105,225,true),
+	"saddlebrown"         : color.RGB(139,69,19,true),
+	"salmon"              : color.RGB(250,128,114,true),
+	"sandybrown"          : color.RGB(244,164,96,true),
+	"seagreen"            : color.RGB(46,139,87,true),
+	"seashell"            : color.RGB(255,245,238,true),
+	"sienna"              : color.RGB(160,82,45,true),
+	"silver"              : color.RGB(192,192,192,true),
+	"skyblue"             : color.RGB(135,206,235,true),
+	"slateblue"           : color.RGB(106,90,205,true),
+	"slategray"           : color.RGB(112,128,144,true),
+	"slategrey"           : color.RGB(112,128,144,true),
+	"snow"                : color.RGB(255,250,250,true),
+	"springgreen"         : color.RGB(0,255,127,true),
+	"steelblue"           : color.RGB(70,130,180,true),
+	"tan"                 : color.RGB(210,180,140,true),
+	"teal"                : color.RGB(0,128,128,true),
+	"thistle"             : color.RGB(216,191,216,true),
+	"tomato"              : color.RGB(255,99,71,true),
+	"turquoise"           : color.RGB(64,224,208,true),
+	"violet"              : color.RGB(238,130,238,true),
+	"wheat"               : color.RGB(245,222,179,true),
+	"white"               : color.RGB(255,255,255,true),
+	"whitesmoke"          : color.RGB(245,245,245,true),
+	"yellow"              : color.RGB(255,255,0,true),
+	"yellowgreen"         : color.RGB(154,205,50,true),
+	"transparent"         : color.RGB(0,0,0,true),
+}
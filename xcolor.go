@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+
+/* An xterm OSC color report, e.g. "\e]11;rgb:ffff/ffff/ffff\e\\", with the
+ * color spec in the first group. The string terminator may be pasted with its
+ * backslash escaped. */
+var oscColorPattern = regexp.MustCompile("^\x1b\\](?:[0-9]+;)+([^\x07\x1b]*)(?:\x07|\x1b(?:\\\\){1,2})?$")
+
+
+/* The color spec in an xterm OSC color report, or colorName itself. */
+func stripOSC(colorName string) string {
+	s := strings.ReplaceAll(escapeNotations.Replace(colorName), `\a`, "\x07")
+	if match := oscColorPattern.FindStringSubmatch(s); match != nil {
+		return match[1]
+	}
+	return colorName
+}
+
+
+/* Parse the device-independent color specs of XParseColor(3):
+ *   rgb:r/g/b              1 to 4 hex digits per component
+ *   rgbi:r/g/b             intensities from 0 to 1
+ *   CIEXYZ:X/Y/Z
+ *   CIEuvY:u/v/Y           u'v' chromaticity
+ *   CIExyY:x/y/Y
+ *   CIELab:L/a/b
+ *   CIELuv:L/u/v
+ *   TekHVC:H/V/C
+ * The prefix is case-insensitive. The CIE spaces and TekHVC are relative to
+ * D65. An xterm OSC color report around the spec is ignored. */
+func parseXColor(colorName string) (rgbColor color.RGBColor, err error) {
+	spec := strings.TrimSpace(stripOSC(colorName))
+	colon := strings.IndexByte(spec, ':')
+	if colon < 0 {
+		err = fmt.Errorf("not an X color spec: %q", colorName)
+		return
+	}
+	prefix := strings.ToLower(spec[:colon])
+	components := strings.Split(spec[colon+1:], "/")
+
+	knownPrefixes := []string{"rgb", "rgbi", "ciexyz", "cieuvy", "ciexyy", "cielab", "cieluv", "tekhvc"}
+	if ! containsString(knownPrefixes, prefix) {
+		err = fmt.Errorf("not an X color spec: %q", colorName)
+		return
+	}
+	if len(components) != 3 {
+		err = &invalidColorError{fmt.Sprintf("%s: expected 3 components separated by /, got %d", spec, len(components))}
+		return
+	}
+
+	var values [3]float64
+	for i, component := range components {
+		if prefix == "rgb" {
+			values[i], err = parseXHexComponent(component)
+		} else {
+			values[i], err = parseNumber(component)
+			if err != nil {
+				err = fmt.Errorf("invalid number %q", component)
+			}
+		}
+		if err != nil {
+			err = &invalidColorError{fmt.Sprintf("%s: %s", spec, err)}
+			return
+		}
+	}
+
+	var r, g, b float64
+	switch prefix {
+	case "rgb":
+		r, g, b = values[0], values[1], values[2]
+	case "rgbi":
+		for _, v := range values {
+			if v < 0 || v > 1 {
+				err = &invalidColorError{fmt.Sprintf("%s: intensities must be from 0 to 1", spec)}
+				return
+			}
+		}
+		r, g, b = values[0], values[1], values[2]
+	case "ciexyz":
+		r, g, b = xyzToSRGB(values[0], values[1], values[2])
+	case "cieuvy":
+		r, g, b = xyzToSRGB(uvYToXYZ(values[0], values[1], values[2]))
+	case "ciexyy":
+		r, g, b = xyzToSRGB(xyYToXYZ(values[0], values[1], values[2]))
+	case "cielab":
+		r, g, b = labToSRGB(values[0], values[1], values[2], whiteD65)
+	case "cieluv":
+		r, g, b = xyzToSRGB(uvYToXYZ(luvToUVY(values[0], values[1], values[2], whiteD65)))
+	case "tekhvc":
+		r, g, b = xyzToSRGB(uvYToXYZ(tekHVCToUVY(values[0], values[1], values[2], whiteD65)))
+	}
+
+	rgbColor = rgbFromFloats(r, g, b)
+	if ! inSRGBGamut(r, g, b) {
+		err = &outOfGamutError{colorName, rgbColor}
+	}
+	return
+}
+
+
+/* A component of an rgb: spec, scaled from its number of hex digits: "f" and
+ * "ffff" are both 1. */
+func parseXHexComponent(component string) (float64, error) {
+	if len(component) < 1 || len(component) > 4 {
+		return 0, fmt.Errorf("component %q must have 1 to 4 hex digits", component)
+	}
+	n, err := strconv.ParseUint(component, 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hex component %q", component)
+	}
+	return float64(n) / float64(uint64(1) << (4 * len(component)) - 1), nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+)
+
+
+func TestParseXColor(t *testing.T) {
+	testParser(t, "parseXColor", func(s string) (string, error) {
+		rgbColor, err := parseXColor(s)
+		return rgbColor.Hex(), err
+	}, []parseCase{
+		{"rgb:ff/80/00", "ff8000"},
+		{"rgb:f/8/0", "ff8800"},
+		{"rgb:ffff/8080/0000", "ff8000"},
+		{"RGB:FFF/000/000", "ff0000"},
+		{"rgbi:1/0.5/0", "ff8000"},
+		{"CIEXYZ:0.9505/1.0/1.089", "ffffff"},
+		{"CIExyY:0.3127/0.329/1", "ffffff"},
+		{"CIEuvY:0.1978/0.4683/1", "ffffff"},
+		{"CIELab:53.24/80.09/67.20", "ff0000"},
+		{"CIELuv:100/0/0", "ffffff"},
+		{"TekHVC:0/100/0", "ffffff"},
+		{"\x1b]11;rgb:ffff/ffff/ffff\x1b\\", "ffffff"},
+		{`\e]11;rgb:ffff/ffff/ffff\a`, "ffffff"},
+		{"rgb:ff/80", ""},
+		{"rgbi:2/0/0", ""},
+		{"rgb:fffff/0/0", ""},
+		{"rgb:gg/0/0", ""},
+		{"rgbi:NaN/0/0", ""},
+		{"CIEXYZ:NaN/1/1", ""},
+		{"CIELab:inf/0/0", ""},
+		{"ff8000", ""},
+	})
+
+	rgbColor, err := parseXColor("CIELab:50/150/0")
+	if ! isOutOfGamut(err) || rgbColor.Hex() != "ff007e" {
+		t.Errorf("parseXColor(\"CIELab:50/150/0\") = #%s, %v, want #ff007e out of gamut", rgbColor.Hex(), err)
+	}
+}
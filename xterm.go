@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gookit/color"
+	"github.com/xo/terminfo"
+)
+
+
+/* The xterm 256 color palette: the 16 ANSI colors (xterm's defaults), a
+ * 6x6x6 color cube and a 24 step grayscale ramp. */
+var xterm256Palette = newXterm256Palette()
+
+/* CIE L*a*b* of each xterm256Palette entry, for nearest color searches. */
+var xterm256Labs = paletteLabs(xterm256Palette[:])
+
+
+var ansi16Palette = [16]color.RGBColor{
+	color.RGB(0, 0, 0, true),
+	color.RGB(205, 0, 0, true),
+	color.RGB(0, 205, 0, true),
+	color.RGB(205, 205, 0, true),
+	color.RGB(0, 0, 238, true),
+	color.RGB(205, 0, 205, true),
+	color.RGB(0, 205, 205, true),
+	color.RGB(229, 229, 229, true),
+	color.RGB(127, 127, 127, true),
+	color.RGB(255, 0, 0, true),
+	color.RGB(0, 255, 0, true),
+	color.RGB(255, 255, 0, true),
+	color.RGB(92, 92, 255, true),
+	color.RGB(255, 0, 255, true),
+	color.RGB(0, 255, 255, true),
+	color.RGB(255, 255, 255, true),
+}
+
+
+var ansi16Names = [16]string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"brightblack", "brightred", "brightgreen", "brightyellow", "brightblue", "brightmagenta", "brightcyan", "brightwhite",
+}
+
+
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+
+func newXterm256Palette() (palette [256]color.RGBColor) {
+	copy(palette[:16], ansi16Palette[:])
+	for i := 0; i < 216; i++ {
+		palette[16 + i] = color.RGB(cubeLevels[i / 36], cubeLevels[i / 6 % 6], cubeLevels[i % 6], true)
+	}
+	for i := 0; i < 24; i++ {
+		gray := uint8(8 + 10 * i)
+		palette[232 + i] = color.RGB(gray, gray, gray, true)
+	}
+	return
+}
+
+
+func paletteLabs(palette []color.RGBColor) [][3]float64 {
+	labs := make([][3]float64, len(palette))
+	for i, rgbColor := range palette {
+		labs[i][0], labs[i][1], labs[i][2] = rgbToLab(rgbColor)
+	}
+	return labs
+}
+
+
+/* The index in [first, last] of the palette color closest to rgbColor by
+ * CIEDE2000, and its distance. */
+func nearestPaletteIndex(rgbColor color.RGBColor, labs [][3]float64, first, last int) (index int, distance float64) {
+	l, a, b := rgbToLab(rgbColor)
+	index, distance = first, -1
+	for i := first; i <= last; i++ {
+		d := ciede2000(l, a, b, labs[i][0], labs[i][1], labs[i][2])
+		if distance < 0 || d < distance {
+			index, distance = i, d
+		}
+	}
+	return
+}
+
+
+/* The closest color in the xterm 256 color cube or grayscale ramp. The 16
+ * ANSI colors are skipped, since terminals often redefine them. */
+func rgbToXterm256(rgbColor color.RGBColor) (index uint8, distance float64) {
+	i, distance := nearestPaletteIndex(rgbColor, xterm256Labs, 16, 255)
+	return uint8(i), distance
+}
+
+
+/* The closest of the 16 ANSI colors, assuming xterm's default palette. */
+func rgbToANSI16(rgbColor color.RGBColor) (index uint8, distance float64) {
+	i, distance := nearestPaletteIndex(rgbColor, xterm256Labs, 0, 15)
+	return uint8(i), distance
+}
+
+
+/* The SGR foreground color for an ANSI color index, 30-37 or 90-97. */
+func ansi16Foreground(index uint8) color.Color {
+	if index < 8 {
+		return color.Color(30 + index)
+	}
+	return color.Color(90 + index - 8)
+}
+
+
+/* The SGR background color for an ANSI color index, 40-47 or 100-107. */
+func ansi16Background(index uint8) color.Color {
+	if index < 8 {
+		return color.Color(40 + index)
+	}
+	return color.Color(100 + index - 8)
+}
+
+
+/* Print label on the closest xterm 256 color, followed by that color's index
+ * and exact value. */
+func printXterm256(rgbColor color.RGBColor, label string) {
+	index, _ := rgbToXterm256(rgbColor)
+	color.C256(index, true).Println(fmt.Sprintf("%s (xterm %d #%s)", label, index, xterm256Palette[index].Hex()))
+}
+
+
+/* Print label on the closest ANSI color, followed by that color's index and
+ * name. */
+func printANSI16(rgbColor color.RGBColor, label string) {
+	index, _ := rgbToANSI16(rgbColor)
+	ansi16Background(index).Println(fmt.Sprintf("%s (ansi %d %s)", label, index, ansi16Names[index]))
+}
+
+
+/* Print swatches of rgbColor in truecolor, as its closest xterm 256 color and
+ * as its closest ANSI color side by side, each labelled with the index and
+ * CIEDE2000 error. The xterm and ANSI swatches use the terminal's own palette,
+ * so they show what the color would look like on a terminal without truecolor.
+ * No swatch is drawn above the current color level: with -color 16, all three
+ * are ANSI colors. */
+func printFallbackPreview(rgbColor color.RGBColor, label string, swatch swatchOptions) {
+	c256, distance256 := rgbToXterm256(rgbColor)
+	c16, distance16 := rgbToANSI16(rgbColor)
+	swatchColor := color.RGB(rgbColor[0], rgbColor[1], rgbColor[2], true)
+
+	columns := []struct {
+		level  terminfo.ColorLevel
+		title  string
+		detail string
+	}{
+		{terminfo.ColorLevelMillions, "truecolor", "#" + rgbColor.Hex()},
+		{terminfo.ColorLevelHundreds, fmt.Sprintf("xterm %d", c256), fmt.Sprintf("ΔE %.2f", distance256)},
+		{terminfo.ColorLevelBasic, fmt.Sprintf("ansi %d %s", c16, ansi16Names[c16]), fmt.Sprintf("ΔE %.2f", distance16)},
+	}
+
+	width := swatch.width
+	for _, column := range columns {
+		for _, text := range []string{column.title, column.detail} {
+			if n := utf8.RuneCountInString(text); n > width {
+				width = n
+			}
+		}
+	}
+	height := swatch.height
+	if height < 1 {
+		height = 1
+	}
+
+	if len(label) > 0 {
+		fmt.Println(label)
+	}
+	for row := 0; row < height; row++ {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = paintAt(column.level, strings.Repeat(" ", width), swatchColor)
+		}
+		fmt.Println(strings.Join(cells, "  "))
+	}
+	for _, text := range []func(i int) string{
+		func(i int) string { return columns[i].title },
+		func(i int) string { return columns[i].detail },
+	} {
+		cells := make([]string, len(columns))
+		for i := range columns {
+			cells[i] = padRight(text(i), width)
+		}
+		fmt.Println(strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+}
+
+
+/* Pad s with spaces to width columns, counting runes rather than bytes. */
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width - n)
+	}
+	return s
+}